@@ -21,4 +21,5 @@ require (
 	github.com/syseleven/go-metakube v0.0.0-20211112103958-da9c0d9da26c
 	go.uber.org/zap v1.19.0
 	golang.org/x/mod v0.5.0
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,161 @@
+package metakube
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/project"
+	"gopkg.in/yaml.v2"
+)
+
+func dataSourceMetakubeClusterKubeconfig() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: metakubeDataSourceClusterKubeconfigRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"raw_config": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Kubeconfig of the cluster admin",
+			},
+
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Kubernetes API server URL",
+			},
+
+			"cluster_ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PEM encoded CA certificate of the API server",
+			},
+
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Token of the cluster admin",
+			},
+		},
+	}
+}
+
+// metakubeKubeconfig is the part of a kubeconfig needed to connect to a cluster.
+type metakubeKubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token string `yaml:"token"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+func metakubeDataSourceClusterKubeconfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+
+	hp := project.NewGetClusterHealthV2Params().WithContext(ctx).WithProjectID(projectID).WithClusterID(clusterID)
+	health, err := k.client.Project.GetClusterHealthV2(hp, k.auth)
+	if err != nil {
+		return diag.Errorf("unable to get cluster '%s' health: %s", clusterID, stringifyResponseError(err))
+	}
+	if !metakubeClusterIsReady(health.Payload) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("cluster '%s' is not ready, kubeconfig is not available yet", clusterID),
+			Detail:   fmt.Sprintf("cluster health: %+v", health.Payload),
+		}}
+	}
+
+	raw, err := metakubeClusterUpdateKubeconfig(ctx, k, projectID, clusterID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if raw == "" {
+		return diag.Errorf("cluster '%s' returned empty kubeconfig", clusterID)
+	}
+	host, ca, token, err := metakubeParseKubeconfig(raw)
+	if err != nil {
+		return diag.Errorf("unable to parse kubeconfig of cluster '%s': %v", clusterID, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", projectID, clusterID))
+	_ = d.Set("raw_config", raw)
+	_ = d.Set("host", host)
+	_ = d.Set("cluster_ca_certificate", ca)
+	_ = d.Set("token", token)
+	return nil
+}
+
+// metakubeParseKubeconfig returns server, decoded CA certificate and token of the current context.
+func metakubeParseKubeconfig(raw string) (string, string, string, error) {
+	var conf metakubeKubeconfig
+	if err := yaml.Unmarshal([]byte(raw), &conf); err != nil {
+		return "", "", "", err
+	}
+	if len(conf.Contexts) == 0 || len(conf.Clusters) == 0 || len(conf.Users) == 0 {
+		return "", "", "", fmt.Errorf("kubeconfig has no context, cluster or user")
+	}
+
+	clusterName, userName := conf.Contexts[0].Context.Cluster, conf.Contexts[0].Context.User
+	for _, v := range conf.Contexts {
+		if v.Name == conf.CurrentContext {
+			clusterName, userName = v.Context.Cluster, v.Context.User
+		}
+	}
+
+	var host, ca, token string
+	for _, v := range conf.Clusters {
+		if v.Name != clusterName {
+			continue
+		}
+		host = v.Cluster.Server
+		b, err := base64.StdEncoding.DecodeString(v.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return "", "", "", fmt.Errorf("invalid certificate-authority-data: %v", err)
+		}
+		ca = string(b)
+	}
+	for _, v := range conf.Users {
+		if v.Name == userName {
+			token = v.User.Token
+		}
+	}
+	if host == "" {
+		return "", "", "", fmt.Errorf("cluster '%s' of current context not found", clusterName)
+	}
+	return host, ca, token, nil
+}
@@ -0,0 +1,84 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceMetakubeClusterValidation takes the inputs of metakube_cluster and validates them without creating a cluster.
+func dataSourceMetakubeClusterValidation() *schema.Resource {
+	clusterSchema := metakubeResourceCluster().Schema
+	s := make(map[string]*schema.Schema)
+	for _, name := range []string{"project_id", "dc_name", "name", "labels", "sshkeys", "provider_override", "spec"} {
+		s[name] = clusterSchema[name]
+	}
+	s["valid"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "True if validation reported no errors",
+	}
+	s["messages"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Errors and warnings reported by validation",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"severity": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"summary": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"detail": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"attribute_path": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		ReadContext: metakubeDataSourceClusterValidationRead,
+		Schema:      s,
+	}
+}
+
+func metakubeDataSourceClusterValidationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k, diagnostics := metakubeResourceClusterProviderMeta(d, m)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	ret := metakubeResourceClusterValidateClusterFields(ctx, d, k)
+
+	d.SetId(fmt.Sprintf("%s:%s:%s", d.Get("project_id").(string), d.Get("dc_name").(string), d.Get("name").(string)))
+	_ = d.Set("valid", !ret.HasError())
+	_ = d.Set("messages", metakubeClusterValidationFlattenMessages(ret))
+	return nil
+}
+
+func metakubeClusterValidationFlattenMessages(in diag.Diagnostics) []interface{} {
+	ret := make([]interface{}, 0, len(in))
+	for _, v := range in {
+		severity := "error"
+		if v.Severity == diag.Warning {
+			severity = "warning"
+		}
+		ret = append(ret, map[string]interface{}{
+			"severity":       severity,
+			"summary":        v.Summary,
+			"detail":         v.Detail,
+			"attribute_path": attributePathString(v.AttributePath),
+		})
+	}
+	return ret
+}
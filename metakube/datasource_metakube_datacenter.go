@@ -0,0 +1,145 @@
+package metakube
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func dataSourceMetakubeDatacenter() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceMetakubeDatacenterRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Datacenter name, e.g. syseleven-dbl1",
+			},
+			"cloud_provider": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Cloud provider of clusters in the datacenter, e.g. openstack",
+			},
+			"seed": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Seed the datacenter belongs to",
+			},
+			"country": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Country code of the datacenter location",
+			},
+			"location": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Location of the datacenter",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Cloud provider region of the datacenter",
+			},
+			"availability_zone": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "OpenStack availability zone of the datacenter",
+			},
+			"enforce_floating_ip": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Nodes get a floating IP assigned regardless of node deployment settings",
+			},
+			"enforce_audit_logging": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Audit logging is enabled for all clusters",
+			},
+			"enforce_pod_security_policy": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Pod security policy admission plugin is enabled for all clusters",
+			},
+			"images": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Default images by operating system",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceMetakubeDatacenterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	name := d.Get("name").(string)
+
+	datacenters, err := metakubeListDatacenters(ctx, k)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var dc *models.Datacenter
+	available := make([]string, 0)
+	for _, v := range datacenters {
+		// Entries without a seed describe seeds, clusters can't be created in them.
+		if v == nil || v.Metadata == nil || v.Spec == nil || v.Spec.Seed == "" {
+			continue
+		}
+		available = append(available, v.Metadata.Name)
+		if v.Metadata.Name == name {
+			dc = v
+		}
+	}
+	if dc == nil {
+		sort.Strings(available)
+		return diag.Errorf("could not find datacenter with name '%s', available datacenters: %s", name, strings.Join(available, ", "))
+	}
+
+	provider := metakubeDatacenterCloudProvider(dc)
+	if provider == "" {
+		provider = dc.Spec.Provider
+	}
+
+	var region, availabilityZone string
+	var enforceFloatingIP bool
+	images := make(map[string]interface{})
+	switch {
+	case dc.Spec.Openstack != nil:
+		region = dc.Spec.Openstack.Region
+		availabilityZone = dc.Spec.Openstack.AvailabilityZone
+		enforceFloatingIP = dc.Spec.Openstack.EnforceFloatingIP
+		for osName, image := range dc.Spec.Openstack.Images {
+			images[osName] = image
+		}
+	case dc.Spec.Aws != nil:
+		region = dc.Spec.Aws.Region
+		for osName, image := range dc.Spec.Aws.Images {
+			images[osName] = image
+		}
+	case dc.Spec.Azure != nil:
+		region = dc.Spec.Azure.Location
+	}
+
+	d.SetId(name)
+	_ = d.Set("cloud_provider", provider)
+	_ = d.Set("seed", dc.Spec.Seed)
+	_ = d.Set("country", dc.Spec.Country)
+	_ = d.Set("location", dc.Spec.Location)
+	_ = d.Set("region", region)
+	_ = d.Set("availability_zone", availabilityZone)
+	_ = d.Set("enforce_floating_ip", enforceFloatingIP)
+	_ = d.Set("enforce_audit_logging", dc.Spec.EnforceAuditLogging)
+	_ = d.Set("enforce_pod_security_policy", dc.Spec.EnforcePodSecurityPolicy)
+	_ = d.Set("images", images)
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package metakube
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func dataSourceMetakubeDatacenters() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceMetakubeDatacentersRead,
+		Schema: map[string]*schema.Schema{
+			"cloud_provider": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"openstack", "aws", "azure", "hetzner", "digitalocean", "vsphere"}, false),
+				Description:  "Only return datacenters of the cloud provider",
+			},
+			"seed": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return datacenters of the seed",
+			},
+			"datacenters": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Matching datacenters sorted by name",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Datacenter name",
+						},
+						"cloud_provider": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Cloud provider of clusters in the datacenter",
+						},
+						"seed": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Seed the datacenter belongs to",
+						},
+						"country": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Country code of the datacenter location",
+						},
+						"location": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Location of the datacenter",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceMetakubeDatacentersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+
+	datacenters, err := metakubeListDatacenters(ctx, k)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ret := metakubeFilterDatacenters(datacenters, d.Get("cloud_provider").(string), d.Get("seed").(string))
+	names := make([]string, 0, len(ret))
+	for _, v := range ret {
+		names = append(names, v.(map[string]interface{})["name"].(string))
+	}
+
+	d.SetId(metakubeSHA256([]byte(strings.Join(names, ","))))
+	_ = d.Set("datacenters", ret)
+
+	return nil
+}
+
+// metakubeFilterDatacenters returns flattened datacenters matching the filters, sorted by name.
+func metakubeFilterDatacenters(in []*models.Datacenter, cloudProvider, seed string) []interface{} {
+	matching := make([]*models.Datacenter, 0)
+	for _, v := range in {
+		// Entries without a seed describe seeds, clusters can't be created in them.
+		if v == nil || v.Metadata == nil || v.Spec == nil || v.Spec.Seed == "" {
+			continue
+		}
+		if cloudProvider != "" && metakubeDatacenterCloudProvider(v) != cloudProvider {
+			continue
+		}
+		if seed != "" && v.Spec.Seed != seed {
+			continue
+		}
+		matching = append(matching, v)
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].Metadata.Name < matching[j].Metadata.Name
+	})
+
+	ret := make([]interface{}, 0, len(matching))
+	for _, v := range matching {
+		ret = append(ret, map[string]interface{}{
+			"name":           v.Metadata.Name,
+			"cloud_provider": metakubeDatacenterCloudProvider(v),
+			"seed":           v.Spec.Seed,
+			"country":        v.Spec.Country,
+			"location":       v.Spec.Location,
+		})
+	}
+	return ret
+}
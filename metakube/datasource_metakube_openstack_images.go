@@ -0,0 +1,129 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/openstack"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func dataSourceMetakubeOpenstackImages() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: metakubeDataSourceOpenstackImagesRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"os": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return images of this operating system, e.g. ubuntu or flatcar",
+			},
+
+			"images": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"os": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"os_version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func metakubeDataSourceOpenstackImagesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+
+	clusterID := d.Get("cluster_id").(string)
+	projectID := d.Get("project_id").(string)
+	if projectID == "" {
+		var err error
+		projectID, err = metakubeResourceClusterFindProjectID(ctx, clusterID, k)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if projectID == "" {
+			return diag.Errorf("could not find project of cluster '%s'", clusterID)
+		}
+	}
+
+	cluster, ok, err := metakubeGetCluster(ctx, projectID, clusterID, k)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !ok || cluster.Spec == nil || cluster.Spec.Cloud == nil {
+		return diag.Errorf("could not find cluster '%s'", clusterID)
+	}
+	seed, err := metakubeGetDatacenterSeed(ctx, k, cluster.Spec.Cloud.DatacenterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	p := openstack.NewListOpenstackImagesNoCredentialsParams().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithDC(seed).
+		WithClusterID(clusterID)
+	r, err := k.client.Openstack.ListOpenstackImagesNoCredentials(p, k.auth)
+	if err != nil {
+		return diag.Errorf("unable to list openstack images: %s", stringifyResponseError(err))
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", clusterID, d.Get("os").(string)))
+	_ = d.Set("project_id", projectID)
+	_ = d.Set("images", metakubeOpenstackImagesFlatten(r.Payload, d.Get("os").(string)))
+	return nil
+}
+
+// metakubeOpenstackImagesFlatten returns active images, filtered by operating system if os is not empty.
+func metakubeOpenstackImagesFlatten(in []*models.Image, os string) []interface{} {
+	result := make([]interface{}, 0, len(in))
+	for _, img := range in {
+		if img == nil || (img.Status != "" && !strings.EqualFold(img.Status, "active")) {
+			continue
+		}
+		distro, _ := img.Metadata["os_distro"].(string)
+		if os != "" && !strings.EqualFold(distro, os) {
+			continue
+		}
+		version, _ := img.Metadata["os_version"].(string)
+		result = append(result, map[string]interface{}{
+			"name":       img.Name,
+			"id":         img.ID,
+			"os":         distro,
+			"os_version": version,
+		})
+	}
+	return result
+}
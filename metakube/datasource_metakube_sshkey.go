@@ -45,7 +45,7 @@ func metakubeDataSourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m
 	prms := project.NewListSSHKeysParams().WithContext(ctx).WithProjectID(prj)
 	res, err := meta.client.Project.ListSSHKeys(prms, meta.auth)
 	if err != nil {
-		return diag.FromErr(err)
+		return diag.Errorf("%s", stringifyResponseError(err))
 	}
 
 	name := d.Get("name").(string)
@@ -0,0 +1,89 @@
+package metakube
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/syseleven/go-metakube/models"
+	"golang.org/x/mod/semver"
+)
+
+func dataSourceMetakubeVersions() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceMetakubeVersionsRead,
+		Schema: map[string]*schema.Schema{
+			"version_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return versions matching the prefix, e.g. 1.21",
+			},
+			"only_default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only return the default version",
+			},
+			"versions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Kubernetes versions supported by MetaKube, newest first",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"latest": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Newest of the returned versions",
+			},
+		},
+	}
+}
+
+func dataSourceMetakubeVersionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	k := meta.(*metakubeProviderMeta)
+
+	all, err := metakubeGetMasterVersions(ctx, k)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	prefix := d.Get("version_prefix").(string)
+	ret := metakubeFilterVersions(all, prefix, d.Get("only_default").(bool))
+	if len(ret) == 0 {
+		return diag.Errorf("no supported version matches the filters, available versions: %s", strings.Join(metakubeFilterVersions(all, "", false), " "))
+	}
+
+	d.SetId(ret[0])
+	_ = d.Set("versions", ret)
+	_ = d.Set("latest", ret[0])
+
+	return nil
+}
+
+// metakubeFilterVersions returns matching versions sorted newest first.
+// Prefix matches whole version segments, so 1.2 matches 1.2.3 but not 1.21.0.
+func metakubeFilterVersions(in []*models.MasterVersion, prefix string, onlyDefault bool) []string {
+	prefix = strings.TrimSuffix(prefix, ".")
+	ret := make([]string, 0)
+	for _, v := range in {
+		if v == nil || (onlyDefault && !v.Default) {
+			continue
+		}
+		s, ok := v.Version.(string)
+		if !ok {
+			continue
+		}
+		if prefix != "" && s != prefix && !strings.HasPrefix(s, prefix+".") {
+			continue
+		}
+		ret = append(ret, s)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return semver.Compare("v"+ret[i], "v"+ret[j]) > 0
+	})
+	return ret
+}
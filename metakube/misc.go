@@ -1,9 +1,17 @@
 package metakube
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/syseleven/go-metakube/models"
 )
 
@@ -12,17 +20,84 @@ func stringifyResponseError(resErr error) string {
 		return ""
 	}
 
+	msg := resErr.Error()
 	rawData, err := json.Marshal(resErr)
 	if err != nil {
-		return err.Error()
+		msg = err.Error()
+	} else {
+		v := &struct {
+			Payload *models.ErrorResponse
+		}{}
+		if err = json.Unmarshal(rawData, &v); err == nil && errorMessage(v.Payload) != "" {
+			msg = errorMessage(v.Payload)
+		}
+	}
+
+	if isForbidden(resErr) {
+		return fmt.Sprintf("forbidden, the token has no permission for this operation, check the role of its user or service account: %s", msg)
+	}
+	return msg
+}
+
+var responseErrorCodeRegexp = regexp.MustCompile(`\]\[(\d{3})\] `)
+
+// responseErrorCode returns http status code of API error response. Default responses carry the code,
+// while dedicated responses, e.g. forbidden, only report it in the error string.
+func responseErrorCode(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+	if e, ok := err.(interface{ Code() int }); ok {
+		return e.Code(), true
+	}
+	m := responseErrorCodeRegexp.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+	code, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+func hasResponseErrorCode(err error, code int) bool {
+	v, ok := responseErrorCode(err)
+	return ok && v == code
+}
+
+func isNotFound(err error) bool {
+	return hasResponseErrorCode(err, http.StatusNotFound)
+}
+
+func isConflict(err error) bool {
+	return hasResponseErrorCode(err, http.StatusConflict)
+}
+
+func isRateLimited(err error) bool {
+	return hasResponseErrorCode(err, http.StatusTooManyRequests)
+}
+
+func isUnauthorized(err error) bool {
+	return hasResponseErrorCode(err, http.StatusUnauthorized)
+}
+
+func isForbidden(err error) bool {
+	return hasResponseErrorCode(err, http.StatusForbidden)
+}
+
+// isTemporaryResponseError checks for errors which are likely to go away on retry,
+// like network timeouts, rate limiting and server errors.
+func isTemporaryResponseError(err error) bool {
+	if err == nil {
+		return false
 	}
-	v := &struct {
-		Payload *models.ErrorResponse
-	}{}
-	if err = json.Unmarshal(rawData, &v); err == nil && errorMessage(v.Payload) != "" {
-		return errorMessage(v.Payload)
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return true
 	}
-	return resErr.Error()
+	code, ok := responseErrorCode(err)
+	return ok && (code == http.StatusTooManyRequests || code >= http.StatusInternalServerError)
 }
 
 func errorMessage(e *models.ErrorResponse) string {
@@ -47,3 +122,22 @@ func int64ToPtr(v int) *int64 {
 	vv := int64(v)
 	return &vv
 }
+
+// attributePathString formats path the way attributes are addressed in ResourceData, e.g. spec.0.version.
+func attributePathString(p cty.Path) string {
+	parts := make([]string, 0, len(p))
+	for _, step := range p {
+		switch v := step.(type) {
+		case cty.GetAttrStep:
+			parts = append(parts, v.Name)
+		case cty.IndexStep:
+			if v.Key.Type() == cty.Number {
+				i, _ := v.Key.AsBigFloat().Int64()
+				parts = append(parts, strconv.FormatInt(i, 10))
+			} else if v.Key.Type() == cty.String {
+				parts = append(parts, v.Key.AsString())
+			}
+		}
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,185 @@
+package metakube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/syseleven/go-metakube/client/project"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func TestStringifyResponseError(t *testing.T) {
+	defaultResponse := func(code int, msg string) error {
+		e := project.NewGetClusterV2Default(code)
+		e.Payload = &models.ErrorResponse{
+			Error: &models.ErrorDetails{
+				Code:    int64ToPtr(code),
+				Message: strToPtr(msg),
+			},
+		}
+		return e
+	}
+
+	cases := []struct {
+		Input          error
+		ExpectedOutput string
+	}{
+		{
+			nil,
+			"",
+		},
+		{
+			defaultResponse(http.StatusNotFound, "not found"),
+			"not found",
+		},
+		{
+			defaultResponse(http.StatusForbidden, "access denied"),
+			"forbidden, the token has no permission for this operation, check the role of its user or service account: access denied",
+		},
+		{
+			project.NewDeleteClusterV2Forbidden(),
+			"forbidden, the token has no permission for this operation, check the role of its user or service account: " + project.NewDeleteClusterV2Forbidden().Error(),
+		},
+	}
+
+	for _, tc := range cases {
+		output := stringifyResponseError(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestResponseErrorCode(t *testing.T) {
+	cases := []struct {
+		Input        error
+		NotFound     bool
+		Conflict     bool
+		RateLimited  bool
+		Unauthorized bool
+		Forbidden    bool
+	}{
+		{
+			Input: nil,
+		},
+		{
+			Input: errors.New("connection refused"),
+		},
+		{
+			Input:    project.NewGetClusterV2Default(http.StatusNotFound),
+			NotFound: true,
+		},
+		{
+			Input:    project.NewPatchClusterV2Default(http.StatusConflict),
+			Conflict: true,
+		},
+		{
+			Input:       project.NewGetClusterV2Default(http.StatusTooManyRequests),
+			RateLimited: true,
+		},
+		{
+			Input:        project.NewGetClusterV2Unauthorized(),
+			Unauthorized: true,
+		},
+		{
+			Input:     project.NewDeleteClusterV2Forbidden(),
+			Forbidden: true,
+		},
+	}
+
+	for _, tc := range cases {
+		output := []bool{isNotFound(tc.Input), isConflict(tc.Input), isRateLimited(tc.Input), isUnauthorized(tc.Input), isForbidden(tc.Input)}
+		expected := []bool{tc.NotFound, tc.Conflict, tc.RateLimited, tc.Unauthorized, tc.Forbidden}
+		if diff := cmp.Diff(expected, output); diff != "" {
+			t.Fatalf("Unexpected output for %v: mismatch (-want +got):\n%s", tc.Input, diff)
+		}
+	}
+}
+
+func TestIsTemporaryResponseError(t *testing.T) {
+	decodeErr := json.Unmarshal([]byte("{"), &struct{}{})
+	cases := []struct {
+		Name     string
+		Input    error
+		Expected bool
+	}{
+		{
+			"nil",
+			nil,
+			false,
+		},
+		{
+			"deadline exceeded",
+			fmt.Errorf("get cluster: %w", context.DeadlineExceeded),
+			true,
+		},
+		{
+			"network timeout",
+			&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "timeout", IsTimeout: true}},
+			true,
+		},
+		{
+			"rate limited",
+			project.NewGetClusterV2Default(http.StatusTooManyRequests),
+			true,
+		},
+		{
+			"server error",
+			project.NewGetClusterV2Default(http.StatusBadGateway),
+			true,
+		},
+		{
+			"not found",
+			project.NewGetClusterV2Default(http.StatusNotFound),
+			false,
+		},
+		{
+			"forbidden",
+			project.NewDeleteClusterV2Forbidden(),
+			false,
+		},
+		{
+			"decode error",
+			decodeErr,
+			false,
+		},
+		{
+			"validation error",
+			errors.New("spec.template in body is required"),
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := isTemporaryResponseError(tc.Input); got != tc.Expected {
+				t.Fatalf("expected %v, got %v", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestAttributePathString(t *testing.T) {
+	cases := []struct {
+		Path     cty.Path
+		Expected string
+	}{
+		{nil, ""},
+		{cty.GetAttrPath("dc_name"), "dc_name"},
+		{cty.GetAttrPath("spec").IndexInt(0).GetAttr("machine_networks").IndexInt(1).GetAttr("gateway"), "spec.0.machine_networks.1.gateway"},
+		{cty.GetAttrPath("labels").IndexString("team"), "labels.team"},
+	}
+
+	for _, tc := range cases {
+		if diff := cmp.Diff(tc.Expected, attributePathString(tc.Path)); diff != "" {
+			t.Fatalf("Unexpected path: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -4,18 +4,24 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/go-homedir"
 	k8client "github.com/syseleven/go-metakube/client"
+	"github.com/syseleven/go-metakube/client/versions"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -23,12 +29,27 @@ import (
 const (
 	// wait this time before starting resource checks
 	requestDelay = time.Second
+
+	// defaultAPITimeout is the default timeout of a single MetaKube API request
+	defaultAPITimeout = "1m"
 )
 
 type metakubeProviderMeta struct {
 	client *k8client.MetaKubeAPI
 	auth   runtime.ClientAuthInfoWriter
 	log    *zap.SugaredLogger
+
+	// used to build clients for resources overriding provider credentials
+	apiTimeout       string
+	terraformVersion string
+
+	skipLiveValidation          bool
+	suppressPasswordAuthWarning bool
+
+	// limits concurrent requests of all clients, nil if unlimited
+	limiter chan struct{}
+
+	masterVersions *metakubeMasterVersionsCache
 }
 
 // Provider returns a schema.Provider for MetaKube.
@@ -75,19 +96,67 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("METAKUBE_LOG_PATH", ""),
 				Description: "Path to store logs",
 			},
+			"skip_live_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("METAKUBE_SKIP_LIVE_VALIDATION", false),
+				Description: "Skip validations of node deployments against cloud provider, like instance sizes and quota",
+			},
+			"skip_credentials_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("METAKUBE_SKIP_CREDENTIALS_VALIDATION", false),
+				Description: "Skip checking that the token can authenticate with MetaKube API on provider configuration, e.g. for offline planning",
+			},
+			"suppress_password_auth_warning": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("METAKUBE_SUPPRESS_PASSWORD_AUTH_WARNING", false),
+				Description: "Don't warn about clusters using OpenStack username and password instead of application credentials",
+			},
+			"api_timeout": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("METAKUBE_API_TIMEOUT", defaultAPITimeout),
+				ValidateDiagFunc: isNonEmptyDurationString,
+				Description:      "Timeout of a single request to MetaKube API, e.g. listing OpenStack networks",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("METAKUBE_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of concurrent requests to MetaKube API across all resources, further requests wait. 0 means no limit",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"metakube_cluster":              metakubeResourceCluster(),
-			"metakube_cluster_role_binding": metakubeResourceClusterRoleBinding(),
-			"metakube_role_binding":         metakubeResourceRoleBinding(),
-			"metakube_node_deployment":      metakubeResourceNodeDeployment(),
-			"metakube_sshkey":               metakubeResourceSSHKey(),
+			"metakube_project":               metakubeResourceProject(),
+			"metakube_project_user":          metakubeResourceProjectUser(),
+			"metakube_service_account":       metakubeResourceServiceAccount(),
+			"metakube_service_account_token": metakubeResourceServiceAccountToken(),
+			"metakube_addon":                 metakubeResourceAddon(),
+			"metakube_constraint":            metakubeResourceConstraint(),
+			"metakube_gatekeeper_config":     metakubeResourceGatekeeperConfig(),
+			"metakube_cluster":               metakubeResourceCluster(),
+			"metakube_cluster_token":         metakubeResourceClusterToken(),
+			"metakube_kubeconfig_file":       metakubeResourceKubeconfigFile(),
+			"metakube_external_cluster":      metakubeResourceExternalCluster(),
+			"metakube_cluster_role_binding":  metakubeResourceClusterRoleBinding(),
+			"metakube_role_binding":          metakubeResourceRoleBinding(),
+			"metakube_node_deployment":       metakubeResourceNodeDeployment(),
+			"metakube_sshkey":                metakubeResourceSSHKey(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"metakube_k8s_version": dataSourceMetakubeK8sClusterVersion(),
-			"metakube_sshkey":      dataSourceMetakubeSSHKey(),
+			"metakube_k8s_version":        dataSourceMetakubeK8sClusterVersion(),
+			"metakube_sshkey":             dataSourceMetakubeSSHKey(),
+			"metakube_openstack_images":   dataSourceMetakubeOpenstackImages(),
+			"metakube_cluster_kubeconfig": dataSourceMetakubeClusterKubeconfig(),
+			"metakube_cluster_validation": dataSourceMetakubeClusterValidation(),
+			"metakube_versions":           dataSourceMetakubeVersions(),
+			"metakube_datacenter":         dataSourceMetakubeDatacenter(),
+			"metakube_datacenters":        dataSourceMetakubeDatacenters(),
 		},
 	}
 
@@ -95,36 +164,79 @@ func Provider() *schema.Provider {
 	// as an example the standard log pkg points to the "old" stderr
 	stderr := os.Stderr
 
-	p.ConfigureContextFunc = func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		terraformVersion := p.TerraformVersion
 		if terraformVersion == "" {
 			// Terraform 0.12 introduced this field to the protocol
 			// We can therefore assume that if it's missing it's 0.10 or 0.11
 			terraformVersion = "0.11+compatible"
 		}
-		return configure(d, terraformVersion, stderr)
+		return configure(ctx, d, terraformVersion, stderr)
 	}
 
 	return p
 }
 
-func configure(d *schema.ResourceData, terraformVersion string, fd *os.File) (interface{}, diag.Diagnostics) {
+func configure(ctx context.Context, d *schema.ResourceData, terraformVersion string, fd *os.File) (interface{}, diag.Diagnostics) {
 	var (
 		k                metakubeProviderMeta
 		diagnostics, tmp diag.Diagnostics
 	)
 
+	k.apiTimeout = d.Get("api_timeout").(string)
+	k.terraformVersion = terraformVersion
+	k.skipLiveValidation = d.Get("skip_live_validation").(bool)
+	k.suppressPasswordAuthWarning = d.Get("suppress_password_auth_warning").(bool)
+	if n := d.Get("max_concurrent_requests").(int); n > 0 {
+		k.limiter = make(chan struct{}, n)
+	}
+	k.masterVersions = &metakubeMasterVersionsCache{}
 	k.log, tmp = newLogger(d, fd)
 	diagnostics = append(diagnostics, tmp...)
-	k.client, tmp = newClient(d.Get("host").(string))
+	k.client, tmp = newClient(d.Get("host").(string), k.apiTimeout, k.limiter)
 	diagnostics = append(diagnostics, tmp...)
 
 	k.auth, tmp = newAuth(d.Get("token").(string), d.Get("token_path").(string), terraformVersion)
 	diagnostics = append(diagnostics, tmp...)
 
+	if !diagnostics.HasError() && !d.Get("skip_credentials_validation").(bool) {
+		diagnostics = append(diagnostics, validateCredentials(ctx, &k, d.Get("host").(string))...)
+	}
+
 	return &k, diagnostics
 }
 
+// validateCredentials makes a lightweight request to check that MetaKube API is reachable and accepts the token.
+func validateCredentials(ctx context.Context, k *metakubeProviderMeta, host string) diag.Diagnostics {
+	_, err := k.client.Versions.GetMasterVersions(versions.NewGetMasterVersionsParams().WithContext(ctx), k.auth)
+	if err == nil {
+		return nil
+	}
+	if isForbidden(err) {
+		// Token is valid, but may lack permissions for some operations, e.g. a viewer token.
+		return diag.Diagnostics{{
+			Severity:      diag.Warning,
+			Summary:       "Authorization token has limited permissions",
+			Detail:        fmt.Sprintf("MetaKube API at %s accepted the token, but denied listing versions: %s", host, stringifyResponseError(err)),
+			AttributePath: cty.Path{cty.GetAttrStep{Name: "token"}},
+		}}
+	}
+	if isUnauthorized(err) {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Authorization token is not valid",
+			Detail:        fmt.Sprintf("MetaKube API at %s rejected the token, please check token or token_path: %s", host, stringifyResponseError(err)),
+			AttributePath: cty.Path{cty.GetAttrStep{Name: "token"}},
+		}}
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       fmt.Sprintf("Can't connect to MetaKube API at %s", host),
+		Detail:        fmt.Sprintf("%s. Set skip_credentials_validation to configure provider without connecting to the API.", stringifyResponseError(err)),
+		AttributePath: cty.Path{cty.GetAttrStep{Name: "host"}},
+	}}
+}
+
 func newLogger(d *schema.ResourceData, fd *os.File) (*zap.SugaredLogger, diag.Diagnostics) {
 	var (
 		ec    zapcore.EncoderConfig
@@ -171,7 +283,7 @@ func newLogger(d *schema.ResourceData, fd *os.File) (*zap.SugaredLogger, diag.Di
 	return zap.New(core).Sugar(), nil
 }
 
-func newClient(host string) (*k8client.MetaKubeAPI, diag.Diagnostics) {
+func newClient(host, apiTimeout string, limiter chan struct{}) (*k8client.MetaKubeAPI, diag.Diagnostics) {
 	u, err := url.Parse(host)
 	if err != nil {
 		return nil, diag.Diagnostics{{
@@ -180,12 +292,90 @@ func newClient(host string) (*k8client.MetaKubeAPI, diag.Diagnostics) {
 			AttributePath: cty.Path{cty.GetAttrStep{Name: "host"}},
 		}}
 	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Host '%s' is not a valid URI, expected e.g. https://metakube.syseleven.de", host),
+			AttributePath: cty.Path{cty.GetAttrStep{Name: "host"}},
+		}}
+	}
+
+	timeout, err := time.ParseDuration(apiTimeout)
+	if err != nil {
+		return nil, diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Can't parse api timeout: %v", err),
+			AttributePath: cty.Path{cty.GetAttrStep{Name: "api_timeout"}},
+		}}
+	}
 
-	return k8client.NewHTTPClientWithConfig(nil, &k8client.TransportConfig{
-		Host:     u.Host,
-		BasePath: u.Path,
-		Schemes:  []string{u.Scheme},
-	}), nil
+	// Requests made with a context ignore the per-request timeout of the transport,
+	// so the timeout is set on the http client to be applied to every request.
+	httpClient := &http.Client{Timeout: timeout}
+	if limiter != nil {
+		httpClient.Transport = &limitedTransport{next: http.DefaultTransport, limiter: limiter}
+	}
+	transport := httptransport.NewWithClient(u.Host, u.Path, []string{u.Scheme}, httpClient)
+	return k8client.New(transport, nil), nil
+}
+
+// limitedTransport queues requests until the number of in-flight requests drops below the limiter capacity.
+// A slot is held until the response body is closed.
+type limitedTransport struct {
+	next    http.RoundTripper
+	limiter chan struct{}
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.limiter <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := func() { <-t.limiter }
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *limitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// withCredentials returns a copy of provider meta that uses a client for given host and token.
+func (k *metakubeProviderMeta) withCredentials(host, token string) (*metakubeProviderMeta, diag.Diagnostics) {
+	client, diagnostics := newClient(host, k.apiTimeout, k.limiter)
+	if diagnostics.HasError() {
+		return nil, diagnostics
+	}
+	auth, diagnostics := newAuth(token, "", k.terraformVersion)
+	if diagnostics.HasError() {
+		return nil, diagnostics
+	}
+	return &metakubeProviderMeta{
+		client:                      client,
+		auth:                        auth,
+		log:                         k.log,
+		apiTimeout:                  k.apiTimeout,
+		terraformVersion:            k.terraformVersion,
+		skipLiveValidation:          k.skipLiveValidation,
+		suppressPasswordAuthWarning: k.suppressPasswordAuthWarning,
+		limiter:                     k.limiter,
+		masterVersions:              &metakubeMasterVersionsCache{},
+	}, nil
 }
 
 func newAuth(token, tokenPath, terraformVersion string) (runtime.ClientAuthInfoWriter, diag.Diagnostics) {
@@ -1,15 +1,23 @@
 package metakube
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 	"text/template"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/syseleven/go-metakube/client/versions"
 )
 
 const (
@@ -49,6 +57,9 @@ const (
 	testEnvAWSSubnetID         = "METAKUBE_AWS_SUBNET_ID"
 	testEnvAWSAvailabilityZone = "METAKUBE_AWS_AVAILABILITY_ZONE"
 	testEnvAWSDiskSize         = "METAKUBE_AWS_DISK_SIZE"
+
+	testEnvHetznerToken  = "METAKUBE_HETZNER_TOKEN"
+	testEnvHetznerNodeDC = "METAKUBE_HETZNER_NODE_DC"
 )
 
 var (
@@ -57,14 +68,24 @@ var (
 )
 
 func init() {
+	resource.AddTestSweepers("metakube_node_deployment", &resource.Sweeper{
+		Name: "metakube_node_deployment",
+		F:    testSweepNodeDeployments,
+	})
 	resource.AddTestSweepers("metakube_cluster", &resource.Sweeper{
-		Name: "metakube_cluster",
-		F:    testSweepClusters,
+		Name:         "metakube_cluster",
+		F:            testSweepClusters,
+		Dependencies: []string{"metakube_node_deployment"},
 	})
 	resource.AddTestSweepers("metakube_sshkey", &resource.Sweeper{
 		Name: "metakube_sshkey",
 		F:    testSweepSSHKeys,
 	})
+	resource.AddTestSweepers("metakube_project", &resource.Sweeper{
+		Name:         "metakube_project",
+		F:            testSweepProjects,
+		Dependencies: []string{"metakube_cluster", "metakube_sshkey"},
+	})
 }
 func TestMain(m *testing.M) {
 	testAccProvider = Provider()
@@ -108,6 +129,13 @@ func testAccPreCheckForAWS(t *testing.T) {
 	checkEnv(t, testEnvAWSNodeDC)
 }
 
+func testAccPreCheckForHetzner(t *testing.T) {
+	t.Helper()
+	testAccPreCheck(t)
+	checkEnv(t, testEnvHetznerToken)
+	checkEnv(t, testEnvHetznerNodeDC)
+}
+
 func testAccPreCheck(t *testing.T) {
 	t.Helper()
 	checkEnv(t, "METAKUBE_HOST")
@@ -155,3 +183,140 @@ func mustParseTemplate(name, text string) *template.Template {
 	}
 	return r
 }
+
+func TestValidateCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Header.Get("Authorization") {
+		case "Bearer valid":
+		case "Bearer viewer":
+			w.WriteHeader(http.StatusForbidden)
+			return
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		Name                  string
+		Host                  string
+		Token                 string
+		ExpectedAttributePath cty.Path
+		ExpectedSeverity      diag.Severity
+	}{
+		{
+			"valid token",
+			server.URL,
+			"valid",
+			nil,
+			diag.Error,
+		},
+		{
+			"invalid token",
+			server.URL,
+			"invalid",
+			cty.GetAttrPath("token"),
+			diag.Error,
+		},
+		{
+			"token without permission",
+			server.URL,
+			"viewer",
+			cty.GetAttrPath("token"),
+			diag.Warning,
+		},
+		{
+			"unreachable host",
+			"http://127.0.0.1:1",
+			"valid",
+			cty.GetAttrPath("host"),
+			diag.Error,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			client, diags := newClient(tc.Host, defaultAPITimeout, nil)
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+			auth, diags := newAuth(tc.Token, "", "")
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+			k := &metakubeProviderMeta{client: client, auth: auth}
+
+			ret := validateCredentials(context.Background(), k, tc.Host)
+			if tc.ExpectedAttributePath == nil {
+				if len(ret) != 0 {
+					t.Fatalf("Unexpected diagnostics: %v", ret)
+				}
+				return
+			}
+			if len(ret) != 1 {
+				t.Fatalf("Expected one diagnostic, got %v", ret)
+			}
+			if !tc.ExpectedAttributePath.Equals(ret[0].AttributePath) {
+				t.Fatalf("Unexpected attribute path: want %#v, got %#v", tc.ExpectedAttributePath, ret[0].AttributePath)
+			}
+			if ret[0].Severity != tc.ExpectedSeverity {
+				t.Fatalf("Unexpected severity: want %v, got %v", tc.ExpectedSeverity, ret[0].Severity)
+			}
+		})
+	}
+}
+
+func TestNewClientInvalidHost(t *testing.T) {
+	for _, host := range []string{"", "metakube.syseleven.de"} {
+		if _, diags := newClient(host, defaultAPITimeout, nil); !diags.HasError() {
+			t.Fatalf("Expected error for host '%s'", host)
+		}
+	}
+}
+
+func TestNewClientLimitsConcurrentRequests(t *testing.T) {
+	const limit = 2
+	var (
+		mu               sync.Mutex
+		current, maxSeen int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		current++
+		if current > maxSeen {
+			maxSeen = current
+		}
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		current--
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	client, diags := newClient(server.URL, defaultAPITimeout, make(chan struct{}, limit))
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3*limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Versions.GetMasterVersions(versions.NewGetMasterVersionsParams().WithContext(context.Background()), nil); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxSeen > limit {
+		t.Fatalf("Expected at most %d concurrent requests, got %d", limit, maxSeen)
+	}
+}
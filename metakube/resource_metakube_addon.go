@@ -0,0 +1,225 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/addon"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func metakubeResourceAddon() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceAddonCreate,
+		ReadContext:   metakubeResourceAddonRead,
+		UpdateContext: metakubeResourceAddonUpdate,
+		DeleteContext: metakubeResourceAddonDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importResourceWithProjectAndClusterID("addon_name"),
+		},
+		CustomizeDiff: validateAddonNameAccessible(),
+
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project the cluster belongs to",
+			},
+
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Cluster to install the addon to",
+			},
+
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Addon name, one of the addons accessible in MetaKube",
+			},
+
+			"variables": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				Description:      "Addon variables as JSON object, e.g. jsonencode({...})",
+			},
+
+			"continuously_reconcile": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Reconcile addon resources continuously, changes made in the cluster are overwritten",
+			},
+		},
+	}
+}
+
+func validateAddonNameAccessible() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		k := meta.(*metakubeProviderMeta)
+		name := d.Get("name").(string)
+		if k.skipLiveValidation || name == "" || !d.HasChange("name") {
+			return nil
+		}
+
+		r, err := k.client.Addon.ListAccessibleAddons(addon.NewListAccessibleAddonsParams().WithContext(ctx), k.auth)
+		if err != nil {
+			k.log.Warnf("skip addon name validation, unable to list accessible addons: %s", stringifyResponseError(err))
+			return nil
+		}
+		for _, v := range r.Payload {
+			if v == name {
+				return nil
+			}
+		}
+		return fmt.Errorf("addon '%s' is not accessible, available addons: %s", name, strings.Join(r.Payload, ", "))
+	}
+}
+
+func metakubeResourceAddonExpand(d *schema.ResourceData) (*models.Addon, error) {
+	ret := &models.Addon{
+		Name: d.Get("name").(string),
+		Spec: &models.AddonSpec{
+			ContinuouslyReconcile: d.Get("continuously_reconcile").(bool),
+		},
+	}
+	if v := d.Get("variables").(string); v != "" {
+		variables, err := structure.ExpandJsonFromString(v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse variables: %v", err)
+		}
+		ret.Spec.Variables = variables
+	}
+	return ret, nil
+}
+
+func metakubeResourceAddonCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	body, err := metakubeResourceAddonExpand(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	p := addon.NewCreateAddonV2Params().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string)).
+		WithBody(body)
+	r, err := k.client.Addon.CreateAddonV2(p, k.auth)
+	if err != nil {
+		return diag.Errorf("unable to install addon '%s': %s", body.Name, stringifyResponseError(err))
+	}
+	d.SetId(r.Payload.ID)
+
+	return metakubeResourceAddonRead(ctx, d, m)
+}
+
+func metakubeResourceAddonRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := addon.NewGetAddonV2Params().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string)).
+		WithAddonID(d.Id())
+	r, err := k.client.Addon.GetAddonV2(p, k.auth)
+	if err != nil {
+		if isNotFound(err) {
+			k.log.Infof("removing addon '%s' from terraform state file, could not find the resource", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to get addon '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	_ = d.Set("name", r.Payload.Name)
+	if spec := r.Payload.Spec; spec != nil {
+		_ = d.Set("continuously_reconcile", spec.ContinuouslyReconcile)
+		variables := ""
+		if len(spec.Variables) > 0 {
+			// Keys of marshaled maps are sorted, so the value does not depend on the order returned by the API.
+			v, err := structure.FlattenJsonToString(spec.Variables)
+			if err != nil {
+				return diag.Errorf("unable to read variables of addon '%s': %v", d.Id(), err)
+			}
+			variables = v
+		}
+		_ = d.Set("variables", variables)
+	}
+
+	return nil
+}
+
+func metakubeResourceAddonUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	body, err := metakubeResourceAddonExpand(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	body.ID = d.Id()
+	p := addon.NewPatchAddonV2Params().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string)).
+		WithAddonID(d.Id()).
+		WithBody(body)
+	if _, err := k.client.Addon.PatchAddonV2(p, k.auth); err != nil {
+		return diag.Errorf("unable to update addon '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	return metakubeResourceAddonRead(ctx, d, m)
+}
+
+func metakubeResourceAddonDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+	p := addon.NewDeleteAddonV2Params().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithClusterID(clusterID).
+		WithAddonID(d.Id())
+	if _, err := k.client.Addon.DeleteAddonV2(p, k.auth); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.Errorf("unable to uninstall addon '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		p := addon.NewGetAddonV2Params().
+			WithContext(ctx).
+			WithProjectID(projectID).
+			WithClusterID(clusterID).
+			WithAddonID(d.Id())
+		if _, err := k.client.Addon.GetAddonV2(p, k.auth); err != nil {
+			if isNotFound(err) {
+				return nil
+			}
+			return resource.NonRetryableError(fmt.Errorf("unable to get addon '%s': %s", d.Id(), stringifyResponseError(err)))
+		}
+		return resource.RetryableError(fmt.Errorf("addon '%s' deletion in progress", d.Id()))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
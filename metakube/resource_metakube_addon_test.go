@@ -0,0 +1,125 @@
+package metakube
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccMetakubeAddon(t *testing.T) {
+	resourceName := "metakube_addon.acctest"
+	params := &testAccCheckMetaKubeAddonBasicParams{
+		ClusterName:                          makeRandomName(),
+		DatacenterName:                       os.Getenv(testEnvOpenstackNodeDC),
+		ProjectID:                            os.Getenv(testEnvProjectID),
+		Version:                              os.Getenv(testEnvK8sVersion),
+		OpenstackApplicationCredentialID:     os.Getenv(testEnvOpenstackApplicationCredentialsID),
+		OpenstackApplicationCredentialSecret: os.Getenv(testEnvOpenstackApplicationCredentialsSecret),
+
+		AddonName:             "cluster-autoscaler",
+		Variables:             `{"b":"2","a":"1"}`,
+		ContinuouslyReconcile: false,
+	}
+	updatedParams := *params
+	updatedParams.Variables = `{"a":"1","b":"3"}`
+	updatedParams.ContinuouslyReconcile = true
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetaKubeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckMetaKubeAddonBasicConfig(t, params),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", params.AddonName),
+					resource.TestCheckResourceAttr(resourceName, "variables", `{"a":"1","b":"2"}`),
+					resource.TestCheckResourceAttr(resourceName, "continuously_reconcile", "false"),
+				),
+			},
+			{
+				Config:   testAccCheckMetaKubeAddonBasicConfig(t, params),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCheckMetaKubeAddonBasicConfig(t, &updatedParams),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "variables", updatedParams.Variables),
+					resource.TestCheckResourceAttr(resourceName, "continuously_reconcile", "true"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("not found")
+					}
+					return fmt.Sprintf("%s:%s:%s", rs.Primary.Attributes["project_id"], rs.Primary.Attributes["cluster_id"], rs.Primary.ID), nil
+				},
+			},
+			// Test importing non-existent resource provides expected error.
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: false,
+				ImportStateId:     "123abc",
+				ExpectError:       regexp.MustCompile(`please provide resource identifier in format 'project_id:cluster_id:addon_name'`),
+			},
+		},
+	})
+}
+
+type testAccCheckMetaKubeAddonBasicParams struct {
+	ClusterName                          string
+	DatacenterName                       string
+	ProjectID                            string
+	Version                              string
+	OpenstackApplicationCredentialID     string
+	OpenstackApplicationCredentialSecret string
+
+	AddonName             string
+	Variables             string
+	ContinuouslyReconcile bool
+}
+
+func testAccCheckMetaKubeAddonBasicConfig(t *testing.T, params *testAccCheckMetaKubeAddonBasicParams) string {
+	t.Helper()
+
+	var result strings.Builder
+	err := mustParseTemplate("addon test template", `
+resource "metakube_cluster" "acctest" {
+	name = "{{ .ClusterName }}"
+	dc_name = "{{ .DatacenterName }}"
+	project_id = "{{ .ProjectID }}"
+
+	spec {
+		version = "{{ .Version }}"
+		cloud {
+			openstack {
+				application_credentials_id="{{ .OpenstackApplicationCredentialID }}"
+				application_credentials_secret="{{ .OpenstackApplicationCredentialSecret }}"
+			}
+		}
+	}
+}
+
+resource "metakube_addon" "acctest" {
+	project_id = "{{ .ProjectID }}"
+	cluster_id = metakube_cluster.acctest.id
+	name = "{{ .AddonName }}"
+	variables = {{ printf "%q" .Variables }}
+	continuously_reconcile = {{ .ContinuouslyReconcile }}
+}
+`).Execute(&result, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.String()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-cty/cty"
@@ -83,6 +84,34 @@ func metakubeResourceCluster() *schema.Resource {
 					ValidateFunc: validation.NoZeroValues,
 				},
 			},
+			"provider_override": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Use these credentials instead of the provider configuration for this cluster",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+							Description:  "The hostname of MetaKube API (in form of URI)",
+						},
+						"token": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validation.NoZeroValues,
+							Description:  "Authentication token",
+						},
+					},
+				},
+			},
+			"force_delete": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Issue the deletion again once if the cluster is stuck in deletion",
+			},
 			"spec": {
 				Type:        schema.TypeList,
 				Required:    true,
@@ -102,6 +131,11 @@ func metakubeResourceCluster() *schema.Resource {
 				Computed:    true,
 				Description: "Deletion timestamp",
 			},
+			"endpoint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of cluster API server",
+			},
 			"kube_config": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -124,6 +158,11 @@ func metakubeResourceCluster() *schema.Resource {
 func metakubeResourceClusterIsVersionDowngraded(_ context.Context, old, new, meta interface{}) bool {
 	// "version" can only be upgraded to newer versions, so we must create a new resource
 	// if it is decreased.
+	// The check sees the configured value even if the diff is suppressed, partial version
+	// of the current one is no downgrade.
+	if metakubeResourceClusterIsPartialVersionOf(new.(string), old.(string)) {
+		return false
+	}
 	newVer, err := version.NewVersion(new.(string))
 	if err != nil {
 		return false
@@ -137,12 +176,35 @@ func metakubeResourceClusterIsVersionDowngraded(_ context.Context, old, new, met
 	return newVer.LessThan(oldVer)
 }
 
+// metakubeResourceClusterProviderMeta returns provider meta built from provider_override if set,
+// or the provider meta otherwise.
+func metakubeResourceClusterProviderMeta(d *schema.ResourceData, m interface{}) (*metakubeProviderMeta, diag.Diagnostics) {
+	k := m.(*metakubeProviderMeta)
+	if _, ok := d.GetOk("provider_override"); !ok {
+		return k, nil
+	}
+	meta, diagnostics := k.withCredentials(d.Get("provider_override.0.host").(string), d.Get("provider_override.0.token").(string))
+	for i := range diagnostics {
+		diagnostics[i].AttributePath = cty.GetAttrPath("provider_override")
+	}
+	return meta, diagnostics
+}
+
 func metakubeResourceClusterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) (diagnostics diag.Diagnostics) {
-	meta := m.(*metakubeProviderMeta)
+	meta, diagnostics := metakubeResourceClusterProviderMeta(d, m)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
 	retDiags := metakubeResourceClusterValidateClusterFields(ctx, d, meta)
 	spec := d.Get("spec").([]interface{})
 	dcname := d.Get("dc_name").(string)
 	clusterSpec := metakubeResourceClusterExpandSpec(spec, dcname)
+	configuredVersion := d.Get("spec.0.version").(string)
+	if v, _, err := metakubeResourceClusterResolveVersion(ctx, meta, configuredVersion); err != nil {
+		return append(retDiags, diag.Errorf("unable to resolve version '%s': %v", configuredVersion, err)...)
+	} else if v != "" {
+		clusterSpec.Version = v
+	}
 	clusterLabels := metakubeResourceClusterLabels(d)
 	resourceProject, err := getProject(meta, d.Get("project_id").(string))
 	if err != nil {
@@ -199,7 +261,7 @@ func metakubeResourceClusterCreate(ctx context.Context, d *schema.ResourceData,
 		})
 	}
 
-	if len(retDiags) > 0 {
+	if retDiags.HasError() {
 		return retDiags
 	}
 
@@ -211,7 +273,7 @@ func metakubeResourceClusterCreate(ctx context.Context, d *schema.ResourceData,
 	}
 	d.SetId(r.Payload.ID)
 
-	if err := assignSSHKeysToCluster(projectID, r.Payload.ID, sshkeys, meta); err != nil {
+	if err := assignSSHKeysToCluster(ctx, projectID, r.Payload.ID, sshkeys, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -219,7 +281,7 @@ func metakubeResourceClusterCreate(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("cluster '%s' is not ready: %v", r.Payload.ID, err)
 	}
 
-	return metakubeResourceClusterRead(ctx, d, m)
+	return append(retDiags, metakubeResourceClusterRead(ctx, d, m)...)
 }
 
 func metakubeResourceClusterLabels(d *schema.ResourceData) map[string]string {
@@ -242,17 +304,16 @@ func metakubeResourceClusterSSHKeys(d *schema.ResourceData) []string {
 
 func metakubeResourceClusterFindDatacenterByName(ctx context.Context, k *metakubeProviderMeta, d *schema.ResourceData) (*models.Datacenter, diag.Diagnostics) {
 	name := d.Get("dc_name").(string)
-	p := datacenter.NewListDatacentersParams().WithContext(ctx)
-	r, err := k.client.Datacenter.ListDatacenters(p, k.auth)
+	datacenters, err := metakubeListDatacenters(ctx, k)
 	if err != nil {
-		return nil, diag.Errorf("Can't list datacenters: %s", stringifyResponseError(err))
+		return nil, diag.FromErr(err)
 	}
 
 	available := make([]string, 0)
 	openstackCluster := metakubeResourceClusterIsOpenstack(d)
 	awsCluster := metakubeResourceClusterIsAWS(d)
 	azureCluster := metakubeResourceClusterIsAzure(d)
-	for _, dc := range r.Payload {
+	for _, dc := range datacenters {
 		openstackDatacenter := dc.Spec.Openstack != nil
 		awsDatacenter := dc.Spec.Aws != nil
 		azureDatacenter := dc.Spec.Azure != nil
@@ -283,6 +344,15 @@ func metakubeResourceClusterFindDatacenterByName(ctx context.Context, k *metakub
 	}}
 }
 
+func metakubeListDatacenters(ctx context.Context, k *metakubeProviderMeta) ([]*models.Datacenter, error) {
+	p := datacenter.NewListDatacentersParams().WithContext(ctx)
+	r, err := k.client.Datacenter.ListDatacenters(p, k.auth)
+	if err != nil {
+		return nil, fmt.Errorf("Can't list datacenters: %s", stringifyResponseError(err))
+	}
+	return r.Payload, nil
+}
+
 func metakubeResourceClusterIsOpenstack(d *schema.ResourceData) bool {
 	return d.Get("spec.0.cloud.0.openstack.#").(int) == 1
 }
@@ -296,7 +366,10 @@ func metakubeResourceClusterIsAWS(d *schema.ResourceData) bool {
 }
 
 func metakubeResourceClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	k := m.(*metakubeProviderMeta)
+	k, diagnostics := metakubeResourceClusterProviderMeta(d, m)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
 
 	projectID := d.Get("project_id").(string)
 	if projectID == "" {
@@ -314,7 +387,7 @@ func metakubeResourceClusterRead(ctx context.Context, d *schema.ResourceData, m
 	}
 	p := project.NewGetClusterV2Params().WithContext(ctx).WithProjectID(projectID).WithClusterID(d.Id())
 	r, err := k.client.Project.GetClusterV2(p, k.auth)
-	if metakubeResourceClusterResponseNotFound(err) {
+	if isNotFound(err) {
 		k.log.Infof("removing cluster '%s', could not find the resource", d.Id())
 		d.SetId("")
 		return nil
@@ -362,13 +435,16 @@ func metakubeResourceClusterRead(ctx context.Context, d *schema.ResourceData, m
 
 	_ = d.Set("deletion_timestamp", r.Payload.DeletionTimestamp.String())
 
+	// Keep last known endpoint while the API does not report it, e.g. during upgrades.
+	if r.Payload.Status != nil && r.Payload.Status.URL != "" {
+		_ = d.Set("endpoint", r.Payload.Status.URL)
+	}
+
 	keys, err := metakubeClusterGetAssignedSSHKeys(ctx, d, k)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	if len(keys) > 0 {
-		d.Set("sshkeys", keys)
-	}
+	_ = d.Set("sshkeys", keys)
 
 	if conf, err := metakubeClusterUpdateKubeconfig(ctx, k, projectID, d.Id()); err != nil {
 		return diag.Diagnostics{{
@@ -492,20 +568,6 @@ func metakubeResourceClusterBelongsToProject(ctx context.Context, prj, id string
 	return false, nil
 }
 
-func metakubeResourceClusterResponseNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	e, ok := err.(*project.GetClusterV2Default)
-	if !ok {
-		return false
-	}
-
-	// All api replies and errors, that nevertheless indicate cluster was deleted.
-	return e.Code() == http.StatusNotFound
-}
-
 func metakubeClusterGetAssignedSSHKeys(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) ([]string, error) {
 	projectID := d.Get("project_id").(string)
 	p := project.NewListSSHKeysAssignedToClusterV2Params().WithProjectID(projectID).WithClusterID(d.Id()).WithContext(ctx)
@@ -530,6 +592,12 @@ type clusterPreserveValues struct {
 	// API returns empty spec for Azure and AWS clusters, so we just preserve values used for creation
 	azure *models.AzureCloudSpec
 	aws   *models.AWSCloudSpec
+	// API does not return Hetzner, DigitalOcean and vSphere credentials
+	hetzner      *models.HetznerCloudSpec
+	digitalocean *models.DigitaloceanCloudSpec
+	vsphere      *models.VSphereCloudSpec
+	// API omits disabled monitoring, so configured block is kept
+	monitoringConfigured bool
 }
 
 type clusterOpenstackPreservedValues struct {
@@ -588,18 +656,51 @@ func readClusterPreserveValues(d *schema.ResourceData) clusterPreserveValues {
 		}
 	}
 
+	var hetzner *models.HetznerCloudSpec
+	if _, ok := d.GetOk(key("hetzner.0")); ok {
+		hetzner = &models.HetznerCloudSpec{
+			Token:   d.Get(key("hetzner.0.token")).(string),
+			Network: d.Get(key("hetzner.0.network")).(string),
+		}
+	}
+
+	var digitalocean *models.DigitaloceanCloudSpec
+	if _, ok := d.GetOk(key("digitalocean.0")); ok {
+		digitalocean = &models.DigitaloceanCloudSpec{
+			Token: d.Get(key("digitalocean.0.token")).(string),
+		}
+	}
+
+	var vsphere *models.VSphereCloudSpec
+	if _, ok := d.GetOk(key("vsphere.0")); ok {
+		vsphere = &models.VSphereCloudSpec{
+			Username: d.Get(key("vsphere.0.username")).(string),
+			Password: d.Get(key("vsphere.0.password")).(string),
+		}
+	}
+
 	return clusterPreserveValues{
 		openstack,
 		azure,
 		aws,
+		hetzner,
+		digitalocean,
+		vsphere,
+		d.Get("spec.0.monitoring.#").(int) > 0,
 	}
 }
 
 func metakubeResourceClusterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	k := m.(*metakubeProviderMeta)
+	k, diagnostics := metakubeResourceClusterProviderMeta(d, m)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
 	projectID := d.Get("project_id").(string)
 
-	var retDiags diag.Diagnostics
+	var (
+		retDiags   diag.Diagnostics
+		newVersion string
+	)
 	if cluster, ok, err := metakubeGetCluster(ctx, projectID, d.Id(), k); err != nil {
 		return diag.FromErr(err)
 	} else if !ok {
@@ -608,14 +709,20 @@ func metakubeResourceClusterUpdate(ctx context.Context, d *schema.ResourceData,
 		return nil
 	} else if d.HasChange("spec.0.version") {
 		k.log.Debugf("validating version change")
-		retDiags = metakubeResourceClusterValidateVersionUpgrade(ctx, projectID, d.Get("spec.0.version").(string), cluster, k)
+		newVersion = d.Get("spec.0.version").(string)
+		if v, _, err := metakubeResourceClusterResolveVersion(ctx, k, newVersion); err != nil {
+			return diag.Errorf("unable to resolve version '%s': %v", newVersion, err)
+		} else if v != "" {
+			newVersion = v
+		}
+		retDiags = metakubeResourceClusterValidateVersionUpgrade(ctx, projectID, newVersion, cluster, k)
 	}
 	retDiags = append(retDiags, metakubeResourceClusterValidateClusterFields(ctx, d, k)...)
 
-	_, diagnostics := metakubeResourceClusterFindDatacenterByName(ctx, k, d)
+	_, diagnostics = metakubeResourceClusterFindDatacenterByName(ctx, k, d)
 	// TODO: delete composed diagnostics, seems to be useless at the moment.
 	retDiags = append(retDiags, diagnostics...)
-	if len(retDiags) > 0 {
+	if retDiags.HasError() {
 		return retDiags
 	}
 
@@ -630,11 +737,20 @@ func metakubeResourceClusterUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if err := metakubeResourceClusterWaitForReady(ctx, k, d.Timeout(schema.TimeoutUpdate), projectID, d.Id()); err != nil {
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	start := time.Now()
+	if newVersion != "" {
+		// Node deployments can only be upgraded after control plane is upgraded.
+		if err := metakubeResourceClusterWaitForVersion(ctx, k, timeout, projectID, d.Id(), newVersion); err != nil {
+			return diag.Errorf("cluster '%s' upgrade did not finish: %v", d.Id(), err)
+		}
+	}
+
+	if err := metakubeResourceClusterWaitForReady(ctx, k, timeout-time.Since(start), projectID, d.Id()); err != nil {
 		return diag.Errorf("cluster '%s' is not ready: %v", d.Id(), err)
 	}
 
-	return metakubeResourceClusterRead(ctx, d, m)
+	return append(retDiags, metakubeResourceClusterRead(ctx, d, m)...)
 }
 
 func metakubeResourceClusterSendPatchReq(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) error {
@@ -646,27 +762,67 @@ func metakubeResourceClusterSendPatchReq(ctx context.Context, d *schema.Resource
 	name := d.Get("name").(string)
 	labels := metakubeResourceClusterGetLabelsChange(d)
 	clusterSpec := metakubeResourceClusterExpandSpec(d.Get("spec").([]interface{}), d.Get("dc_name").(string))
+	configuredVersion := d.Get("spec.0.version").(string)
+	if v, _, err := metakubeResourceClusterResolveVersion(ctx, k, configuredVersion); err != nil {
+		return fmt.Errorf("unable to resolve version '%s': %v", configuredVersion, err)
+	} else if v != "" {
+		clusterSpec.Version = v
+	}
 	p.SetPatch(map[string]interface{}{
 		"name":   name,
 		"labels": labels,
 		"spec":   clusterSpec,
 	})
 
-	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+	if err := metakubeResourceClusterRetryPatch(ctx, d, k, p); err != nil {
+		return err
+	}
+
+	if d.HasChange("spec.0.monitoring") && !d.Get("spec.0.monitoring.0.enabled").(bool) {
+		// Disabled monitoring is omitted from the request above, so we explicitly set it to false.
+		p.SetPatch(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"mla": map[string]interface{}{
+					"monitoringEnabled": false,
+				},
+			},
+		})
+		if err := metakubeResourceClusterRetryPatch(ctx, d, k, p); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("spec.0.default_node_selector") && len(d.Get("spec.0.default_node_selector").(map[string]interface{})) == 0 {
+		// Removed selector is omitted from the request above, so we explicitly unset it.
+		p.SetPatch(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"podNodeSelectorAdmissionPluginConfig": map[string]interface{}{
+					podNodeSelectorClusterDefaultKey: nil,
+				},
+			},
+		})
+		if err := metakubeResourceClusterRetryPatch(ctx, d, k, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func metakubeResourceClusterRetryPatch(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta, p *project.PatchClusterV2Params) error {
+	return resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
 		_, err := k.client.Project.PatchClusterV2(p, k.auth)
 		if err != nil {
-			if e, ok := err.(*project.PatchClusterV2Default); ok && e.Code() == http.StatusConflict {
+			if isConflict(err) {
 				return resource.RetryableError(fmt.Errorf("cluster patch conflict: %v", err))
 			}
+			if isRateLimited(err) {
+				return resource.RetryableError(fmt.Errorf("cluster patch rate limited: %v", err))
+			}
 			return resource.NonRetryableError(fmt.Errorf("patch cluster '%s': %v", d.Id(), stringifyResponseError(err)))
 		}
 		return nil
 	})
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func metakubeResourceClusterGetLabelsChange(d *schema.ResourceData) map[string]interface{} {
@@ -721,26 +877,38 @@ func updateClusterSSHKeys(ctx context.Context, d *schema.ResourceData, k *metaku
 		p.SetKeyID(id)
 		_, err := k.client.Project.DetachSSHKeyFromClusterV2(p, k.auth)
 		if err != nil {
-			if e, ok := err.(*project.DetachSSHKeyFromClusterV2Default); ok && e.Code() == http.StatusNotFound {
+			if isNotFound(err) {
 				continue
 			}
 			return fmt.Errorf("failed to unassign sshkey: %s", stringifyResponseError(err))
 		}
 	}
 
-	if err := assignSSHKeysToCluster(projectID, d.Id(), assign, k); err != nil {
+	if err := assignSSHKeysToCluster(ctx, projectID, d.Id(), assign, k); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func assignSSHKeysToCluster(projectID, clusterID string, sshkeyIDs []string, k *metakubeProviderMeta) error {
+// sshKeyAssignTimeout bounds retries of a single key assignment, which can conflict right after cluster creation.
+const sshKeyAssignTimeout = 2 * time.Minute
+
+func assignSSHKeysToCluster(ctx context.Context, projectID, clusterID string, sshkeyIDs []string, k *metakubeProviderMeta) error {
 	for _, id := range sshkeyIDs {
-		p := project.NewAssignSSHKeyToClusterV2Params().WithProjectID(projectID).WithClusterID(clusterID).WithKeyID(id)
-		_, err := k.client.Project.AssignSSHKeyToClusterV2(p, k.auth)
+		p := project.NewAssignSSHKeyToClusterV2Params().WithContext(ctx).WithProjectID(projectID).WithClusterID(clusterID).WithKeyID(id)
+		err := resource.RetryContext(ctx, sshKeyAssignTimeout, func() *resource.RetryError {
+			_, err := k.client.Project.AssignSSHKeyToClusterV2(p, k.auth)
+			if err != nil {
+				if isConflict(err) || isNotFound(err) || isRateLimited(err) {
+					return resource.RetryableError(fmt.Errorf("assign sshkey '%s': %s", id, stringifyResponseError(err)))
+				}
+				return resource.NonRetryableError(fmt.Errorf("Can't assign sshkeys to cluster '%s': %v", clusterID, err))
+			}
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("Can't assign sshkeys to cluster '%s': %v", clusterID, err)
+			return err
 		}
 	}
 
@@ -760,15 +928,7 @@ func metakubeResourceClusterWaitForReady(ctx context.Context, k *metakubeProvide
 			return resource.RetryableError(fmt.Errorf("unable to get cluster '%s' health: %s", clusterID, stringifyResponseError(err)))
 		}
 
-		const up models.HealthStatus = 1
-
-		if r.Payload.Apiserver == up &&
-			r.Payload.CloudProviderInfrastructure == up &&
-			r.Payload.Controller == up &&
-			r.Payload.Etcd == up &&
-			r.Payload.MachineController == up &&
-			r.Payload.Scheduler == up &&
-			r.Payload.UserClusterControllerManager == up {
+		if metakubeClusterIsReady(r.Payload) {
 			return nil
 		}
 
@@ -777,26 +937,74 @@ func metakubeResourceClusterWaitForReady(ctx context.Context, k *metakubeProvide
 	})
 }
 
+func metakubeClusterIsReady(h *models.ClusterHealth) bool {
+	const up models.HealthStatus = 1
+
+	return h != nil &&
+		h.Apiserver == up &&
+		h.CloudProviderInfrastructure == up &&
+		h.Controller == up &&
+		h.Etcd == up &&
+		h.MachineController == up &&
+		h.Scheduler == up &&
+		h.UserClusterControllerManager == up
+}
+
+func metakubeResourceClusterWaitForVersion(ctx context.Context, k *metakubeProviderMeta, timeout time.Duration, projectID, clusterID, version string) error {
+	var current interface{}
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		cluster, ok, err := metakubeGetCluster(ctx, projectID, clusterID, k)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+		if !ok {
+			return resource.NonRetryableError(fmt.Errorf("cluster '%s' not found", clusterID))
+		}
+
+		if cluster.Status != nil {
+			current = cluster.Status.Version
+			if fmt.Sprint(current) == version {
+				return nil
+			}
+		}
+
+		k.log.Debugf("waiting for cluster '%s' control plane version %s, current %v", clusterID, version, current)
+		return resource.RetryableError(fmt.Errorf("waiting for control plane version %s", version))
+	})
+	if err != nil && current != nil {
+		return fmt.Errorf("%v, control plane runs version %v", err, current)
+	}
+	return err
+}
+
+// clusterDeletionStuckThreshold is the time after which a cluster still being deleted is considered stuck.
+const clusterDeletionStuckThreshold = 10 * time.Minute
+
 func metakubeResourceClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	k := m.(*metakubeProviderMeta)
+	k, diagnostics := metakubeResourceClusterProviderMeta(d, m)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
 	projectID := d.Get("project_id").(string)
 	p := project.NewDeleteClusterV2Params()
 
 	p.SetProjectID(projectID)
 	p.SetClusterID(d.Id())
 
-	deleteSent := false
+	var (
+		deleteSent, stuck   bool
+		deletedAt, forcedAt time.Time
+	)
+	forceDelete := d.Get("force_delete").(bool)
 	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		if !deleteSent {
 			_, err := k.client.Project.DeleteClusterV2(p, k.auth)
 			if err != nil {
-				if e, ok := err.(*project.DeleteClusterV2Default); ok {
-					if e.Code() == http.StatusConflict {
-						return resource.RetryableError(err)
-					}
-					if e.Code() == http.StatusNotFound {
-						return nil
-					}
+				if isConflict(err) || isRateLimited(err) {
+					return resource.RetryableError(err)
+				}
+				if isNotFound(err) {
+					return nil
 				}
 				if _, ok := err.(*project.DeleteClusterV2Forbidden); ok {
 					return nil
@@ -812,10 +1020,11 @@ func metakubeResourceClusterDelete(ctx context.Context, d *schema.ResourceData,
 
 		r, err := k.client.Project.GetClusterV2(p, k.auth)
 		if err != nil {
-			if e, ok := err.(*project.GetClusterV2Default); ok && e.Code() == http.StatusNotFound {
-				k.log.Debugf("cluster '%s' has been destroyed, returned http code: %d", d.Id(), e.Code())
+			if isNotFound(err) {
+				k.log.Debugf("cluster '%s' has been destroyed", d.Id())
 				return nil
-			} else if e.Code() == http.StatusInternalServerError {
+			}
+			if hasResponseErrorCode(err, http.StatusInternalServerError) || isRateLimited(err) {
 				return resource.RetryableError(err)
 			}
 			if _, ok := err.(*project.GetClusterV2Forbidden); ok {
@@ -826,14 +1035,100 @@ func metakubeResourceClusterDelete(ctx context.Context, d *schema.ResourceData,
 
 		k.log.Debugf("cluster '%s' deletion in progress, deletionTimestamp: %s",
 			d.Id(), r.Payload.DeletionTimestamp.String())
+
+		deletedAt = time.Time(r.Payload.DeletionTimestamp)
+		since := deletedAt
+		if !forcedAt.IsZero() {
+			since = forcedAt
+		}
+		if !since.IsZero() && time.Since(since) > clusterDeletionStuckThreshold {
+			if forceDelete && forcedAt.IsZero() {
+				k.log.Infof("cluster '%s' is stuck in deletion, deleting it again", d.Id())
+				forcedAt = time.Now()
+				deleteSent = false
+				return resource.RetryableError(fmt.Errorf("cluster '%s' deletion in progress", d.Id()))
+			}
+			stuck = true
+			return resource.NonRetryableError(fmt.Errorf("cluster '%s' is stuck in deletion", d.Id()))
+		}
 		return resource.RetryableError(fmt.Errorf("cluster '%s' deletion in progress", d.Id()))
 	})
+	if stuck {
+		return metakubeResourceClusterDeletionStuckDiagnostics(ctx, k, projectID, d.Id(), deletedAt, forceDelete)
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	return nil
 }
 
+// metakubeResourceClusterDeletionStuckDiagnostics describes what may block the deletion.
+// MetaKube API doesn't expose finalizers or conditions, so health of components cleaning up and remaining node deployments are reported.
+func metakubeResourceClusterDeletionStuckDiagnostics(ctx context.Context, k *metakubeProviderMeta, projectID, clusterID string, deletedAt time.Time, forced bool) diag.Diagnostics {
+	details := []string{
+		fmt.Sprintf("Deletion started at %s, %s ago.", deletedAt.Format(time.RFC3339), time.Since(deletedAt).Round(time.Second)),
+	}
+
+	hp := project.NewGetClusterHealthV2Params().WithContext(ctx).WithProjectID(projectID).WithClusterID(clusterID)
+	if r, err := k.client.Project.GetClusterHealthV2(hp, k.auth); err == nil {
+		if unhealthy := metakubeClusterUnhealthyComponents(r.Payload); len(unhealthy) > 0 {
+			details = append(details, fmt.Sprintf("Unhealthy components: %s.", strings.Join(unhealthy, ", ")))
+		}
+	}
+
+	mp := project.NewListMachineDeploymentsParams().WithContext(ctx).WithProjectID(projectID).WithClusterID(clusterID)
+	if r, err := k.client.Project.ListMachineDeployments(mp, k.auth); err == nil {
+		var names []string
+		for _, v := range r.Payload {
+			if v != nil {
+				names = append(names, v.Name)
+			}
+		}
+		if len(names) > 0 {
+			details = append(details, fmt.Sprintf("Remaining node deployments: %s.", strings.Join(names, ", ")))
+		}
+	}
+
+	if forced {
+		details = append(details, "Deletion was issued again without effect, please contact support with the cluster ID.")
+	} else {
+		details = append(details, "Set force_delete to issue the deletion again, or contact support with the cluster ID.")
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Cluster '%s' is stuck in deletion", clusterID),
+		Detail:   strings.Join(details, " "),
+	}}
+}
+
+// metakubeClusterUnhealthyComponents returns names of components which are not up.
+func metakubeClusterUnhealthyComponents(h *models.ClusterHealth) []string {
+	const up models.HealthStatus = 1
+
+	if h == nil {
+		return nil
+	}
+	var ret []string
+	for _, v := range []struct {
+		name   string
+		status models.HealthStatus
+	}{
+		{"apiserver", h.Apiserver},
+		{"cloudProviderInfrastructure", h.CloudProviderInfrastructure},
+		{"controller", h.Controller},
+		{"etcd", h.Etcd},
+		{"machineController", h.MachineController},
+		{"scheduler", h.Scheduler},
+		{"userClusterControllerManager", h.UserClusterControllerManager},
+	} {
+		if v.status != up {
+			ret = append(ret, v.name)
+		}
+	}
+	return ret
+}
+
 func getProject(meta *metakubeProviderMeta, id string) (*models.Project, error) {
 	ret, err := meta.client.Project.GetProject(project.NewGetProjectParams().WithProjectID(id), meta.auth)
 	if err != nil {
@@ -15,7 +15,7 @@ import (
 func TestAccMetakubeClusterRoleBinding(t *testing.T) {
 	resourceName := "metakube_cluster_role_binding.acctest"
 	params := &testAccCheckMetaKubeClusterRoleBindingBasicParams{
-		ClusterName:                          randomName("testacc", 5),
+		ClusterName:                          makeRandomName(),
 		DatacenterName:                       os.Getenv(testEnvOpenstackNodeDC),
 		ProjectID:                            os.Getenv(testEnvProjectID),
 		Version:                              os.Getenv(testEnvK8sVersion),
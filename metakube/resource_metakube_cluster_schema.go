@@ -1,9 +1,13 @@
 package metakube
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -14,7 +18,11 @@ func metakubeResourceClusterSpecFields() map[string]*schema.Schema {
 			Type:         schema.TypeString,
 			Required:     true,
 			ValidateFunc: validation.NoZeroValues,
-			Description:  "Cloud orchestrator version, either Kubernetes or OpenShift",
+			DiffSuppressFunc: func(_, old, new string, _ *schema.ResourceData) bool {
+				// Partial version is resolved to the newest patch version on apply.
+				return metakubeResourceClusterIsPartialVersionOf(new, old)
+			},
+			Description: "Cloud orchestrator version, either Kubernetes or OpenShift. Partial version, e.g. 1.28, resolves to the newest available patch version",
 		},
 		"enable_ssh_agent": {
 			Type:        schema.TypeBool,
@@ -68,7 +76,7 @@ func metakubeResourceClusterSpecFields() map[string]*schema.Schema {
 						Elem: &schema.Resource{
 							Schema: metakubeResourceCluserAWSCloudSpecFields(),
 						},
-						ConflictsWith: []string{"spec.0.cloud.0.openstack", "spec.0.cloud.0.azure"},
+						ConflictsWith: []string{"spec.0.cloud.0.openstack", "spec.0.cloud.0.azure", "spec.0.cloud.0.hetzner", "spec.0.cloud.0.digitalocean", "spec.0.cloud.0.vsphere"},
 					},
 					"openstack": {
 						Type:        schema.TypeList,
@@ -78,7 +86,7 @@ func metakubeResourceClusterSpecFields() map[string]*schema.Schema {
 						Elem: &schema.Resource{
 							Schema: metakubeResourceClusterOpenstackCloudSpecFields(),
 						},
-						ConflictsWith: []string{"spec.0.cloud.0.aws", "spec.0.cloud.0.azure"},
+						ConflictsWith: []string{"spec.0.cloud.0.aws", "spec.0.cloud.0.azure", "spec.0.cloud.0.hetzner", "spec.0.cloud.0.digitalocean", "spec.0.cloud.0.vsphere"},
 					},
 					"azure": {
 						Type:        schema.TypeList,
@@ -88,7 +96,37 @@ func metakubeResourceClusterSpecFields() map[string]*schema.Schema {
 						Elem: &schema.Resource{
 							Schema: metakubeResourceClusterAzureSpecFields(),
 						},
-						ConflictsWith: []string{"spec.0.cloud.0.aws", "spec.0.cloud.0.openstack"},
+						ConflictsWith: []string{"spec.0.cloud.0.aws", "spec.0.cloud.0.openstack", "spec.0.cloud.0.hetzner", "spec.0.cloud.0.digitalocean", "spec.0.cloud.0.vsphere"},
+					},
+					"hetzner": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "Hetzner cluster specification",
+						Elem: &schema.Resource{
+							Schema: metakubeResourceClusterHetznerSpecFields(),
+						},
+						ConflictsWith: []string{"spec.0.cloud.0.aws", "spec.0.cloud.0.openstack", "spec.0.cloud.0.azure", "spec.0.cloud.0.digitalocean", "spec.0.cloud.0.vsphere"},
+					},
+					"digitalocean": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "DigitalOcean cluster specification",
+						Elem: &schema.Resource{
+							Schema: metakubeResourceClusterDigitaloceanSpecFields(),
+						},
+						ConflictsWith: []string{"spec.0.cloud.0.aws", "spec.0.cloud.0.openstack", "spec.0.cloud.0.azure", "spec.0.cloud.0.hetzner", "spec.0.cloud.0.vsphere"},
+					},
+					"vsphere": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "vSphere cluster specification",
+						Elem: &schema.Resource{
+							Schema: metakubeResourceClusterVSphereSpecFields(),
+						},
+						ConflictsWith: []string{"spec.0.cloud.0.aws", "spec.0.cloud.0.openstack", "spec.0.cloud.0.azure", "spec.0.cloud.0.hetzner", "spec.0.cloud.0.digitalocean"},
 					},
 				},
 			},
@@ -117,20 +155,25 @@ func metakubeResourceClusterSpecFields() map[string]*schema.Schema {
 			Elem: &schema.Resource{
 				Schema: map[string]*schema.Schema{
 					"cidr": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "Network CIDR",
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.IsCIDR,
+						Description:  "Network CIDR",
 					},
 					"gateway": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "Network gateway",
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.IsIPAddress,
+						Description:  "Network gateway, must be in the network CIDR",
 					},
 					"dns_servers": {
 						Type:        schema.TypeSet,
 						Optional:    true,
 						Description: "DNS servers",
-						Elem:        schema.TypeString,
+						Elem: &schema.Schema{
+							Type:         schema.TypeString,
+							ValidateFunc: validation.IsIPAddress,
+						},
 					},
 				},
 			},
@@ -141,6 +184,22 @@ func metakubeResourceClusterSpecFields() map[string]*schema.Schema {
 			Default:     false,
 			Description: "Whether to enable audit logging or not",
 		},
+		"monitoring": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "User cluster monitoring settings",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"enabled": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Whether to enable user cluster monitoring or not",
+					},
+				},
+			},
+		},
 		"pod_security_policy": {
 			Type:        schema.TypeBool,
 			Optional:    true,
@@ -153,6 +212,15 @@ func metakubeResourceClusterSpecFields() map[string]*schema.Schema {
 			Default:     false,
 			Description: "Configure PodNodeSelector admission plugin at the apiserver",
 		},
+		"default_node_selector": {
+			Type:             schema.TypeMap,
+			Optional:         true,
+			ValidateDiagFunc: validateLabelMap,
+			Description:      "Node selector applied to pods of all namespaces that don't have their own node selector, requires pod_node_selector",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
 		"services_cidr": {
 			Type:        schema.TypeString,
 			Optional:    true,
@@ -239,6 +307,79 @@ func metakubeResourceClusterAzureSpecFields() map[string]*schema.Schema {
 	}
 }
 
+func metakubeResourceClusterHetznerSpecFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"token": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Sensitive:    true,
+			Description:  "Token used to authenticate with the Hetzner cloud API",
+		},
+		"network": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Pre-existing Hetzner network in which the machines are running. If not specified, the network configured on the datacenter will be used",
+		},
+	}
+}
+
+func metakubeResourceClusterDigitaloceanSpecFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"token": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Sensitive:    true,
+			Description:  "Token used to authenticate with the DigitalOcean API",
+		},
+	}
+}
+
+func metakubeResourceClusterVSphereSpecFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"username": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Sensitive:    true,
+			Description:  "vSphere user name",
+		},
+		"password": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Sensitive:    true,
+			Description:  "vSphere user password",
+		},
+		"vm_net_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Name of the vSphere network",
+		},
+		"datastore": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: []string{"spec.0.cloud.0.vsphere.0.datastore", "spec.0.cloud.0.vsphere.0.datastore_cluster"},
+			Description:  "Datastore to be used for storing virtual machines and as a default for dynamic volume provisioning",
+		},
+		"datastore_cluster": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: []string{"spec.0.cloud.0.vsphere.0.datastore", "spec.0.cloud.0.vsphere.0.datastore_cluster"},
+			Description:  "Datastore cluster to be used for storing virtual machines",
+		},
+		"folder": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Folder to be used to group the provisioned virtual machines",
+		},
+	}
+}
+
 func metakubeResourceCluserAWSCloudSpecFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"access_key_id": {
@@ -372,3 +513,55 @@ func metakubeResourceClusterOpenstackCloudSpecFields() map[string]*schema.Schema
 		},
 	}
 }
+
+var (
+	labelNameRegexp   = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	labelPrefixRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// validateLabelMap checks that keys and values of the map are valid Kubernetes label keys and values.
+func validateLabelMap(v interface{}, p cty.Path) diag.Diagnostics {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Should be a map",
+			AttributePath: p,
+		}}
+	}
+
+	var ret diag.Diagnostics
+	for key, value := range m {
+		if err := labelKeyError(key); err != "" {
+			ret = append(ret, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("invalid label key '%s': %s", key, err),
+				AttributePath: p.IndexString(key),
+			})
+		}
+		vv, _ := value.(string)
+		if vv != "" && (len(vv) > 63 || !labelNameRegexp.MatchString(vv)) {
+			ret = append(ret, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("invalid label value '%s': must be 63 characters or less, begin and end with an alphanumeric character and contain only alphanumerics, '-', '_' or '.'", vv),
+				AttributePath: p.IndexString(key),
+			})
+		}
+	}
+	return ret
+}
+
+func labelKeyError(key string) string {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if prefix == "" || len(prefix) > 253 || !labelPrefixRegexp.MatchString(prefix) {
+			return "prefix must be a DNS subdomain of 253 characters or less"
+		}
+	}
+	if name == "" || len(name) > 63 || !labelNameRegexp.MatchString(name) {
+		return "name must be 63 characters or less, begin and end with an alphanumeric character and contain only alphanumerics, '-', '_' or '.'"
+	}
+	return ""
+}
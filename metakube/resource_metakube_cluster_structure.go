@@ -1,9 +1,15 @@
 package metakube
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/syseleven/go-metakube/models"
 )
 
+// podNodeSelectorClusterDefaultKey is the key of PodNodeSelector config holding the selector of namespaces without own selector.
+const podNodeSelectorClusterDefaultKey = "clusterDefaultNodeSelector"
+
 // flatteners
 
 func metakubeResourceClusterFlattenSpec(values clusterPreserveValues, in *models.ClusterSpec) []interface{} {
@@ -32,10 +38,22 @@ func metakubeResourceClusterFlattenSpec(values clusterPreserveValues, in *models
 		att["audit_logging"] = in.AuditLogging.Enabled
 	}
 
+	if monitoring := in.Mla != nil && in.Mla.MonitoringEnabled; monitoring || values.monitoringConfigured {
+		att["monitoring"] = []interface{}{
+			map[string]interface{}{
+				"enabled": monitoring,
+			},
+		}
+	}
+
 	att["pod_security_policy"] = in.UsePodSecurityPolicyAdmissionPlugin
 
 	att["pod_node_selector"] = in.UsePodNodeSelectorAdmissionPlugin
 
+	if v := in.PodNodeSelectorAdmissionPluginConfig[podNodeSelectorClusterDefaultKey]; v != "" {
+		att["default_node_selector"] = flattenNodeSelector(v)
+	}
+
 	if network := in.ClusterNetwork; network != nil {
 		if network.DNSDomain != "" {
 			att["domain_name"] = network.DNSDomain
@@ -74,6 +92,23 @@ func flattenUpdateWindow(in *models.UpdateWindow) []interface{} {
 	return []interface{}{m}
 }
 
+// flattenNodeSelector parses selector in format "key1=value1,key2=value2".
+func flattenNodeSelector(in string) map[string]interface{} {
+	ret := make(map[string]interface{})
+	for _, s := range strings.Split(in, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) == 2 {
+			ret[kv[0]] = kv[1]
+		} else {
+			ret[kv[0]] = ""
+		}
+	}
+	return ret
+}
+
 func flattenMachineNetworks(in []*models.MachineNetworkingConfig) []interface{} {
 	if len(in) < 1 {
 		return []interface{}{}
@@ -122,6 +157,18 @@ func flattenClusterCloudSpec(values clusterPreserveValues, in *models.CloudSpec)
 		att["azure"] = flattenAzureSpec(values.azure)
 	}
 
+	if in.Hetzner != nil {
+		att["hetzner"] = flattenHetznerSpec(values.hetzner, in.Hetzner)
+	}
+
+	if in.Digitalocean != nil {
+		att["digitalocean"] = flattenDigitaloceanSpec(values.digitalocean, in.Digitalocean)
+	}
+
+	if in.Vsphere != nil {
+		att["vsphere"] = flattenVSphereSpec(values.vsphere, in.Vsphere)
+	}
+
 	return []interface{}{att}
 }
 
@@ -208,20 +255,31 @@ func flattenOpenstackSpec(values *clusterOpenstackPreservedValues, in *models.Op
 		att["server_group_id"] = in.ServerGroupID
 	}
 
+	// Secrets are never returned, but the API may return the credential identifiers,
+	// which tells which credentials style is used, e.g. for imported clusters.
+	if in.ApplicationCredentialID != "" {
+		att["application_credentials_id"] = in.ApplicationCredentialID
+	} else if in.Username != "" {
+		att["username"] = in.Username
+		if in.Tenant != "" {
+			att["tenant"] = in.Tenant
+		}
+	}
+
 	if values != nil {
 		if _, ok := att["server_group_id"]; !ok && values.openstackServerGroupID != nil {
 			att["server_group_id"] = values.openstackServerGroupID
 		}
-		if values.openstackTenant != nil {
+		if _, ok := att["tenant"]; !ok && values.openstackTenant != nil {
 			att["tenant"] = values.openstackTenant
 		}
-		if values.openstackUsername != nil {
+		if _, ok := att["username"]; !ok && values.openstackUsername != nil {
 			att["username"] = values.openstackUsername
 		}
 		if values.openstackPassword != nil {
 			att["password"] = values.openstackPassword
 		}
-		if values.openstackApplicationCredentialsID != nil {
+		if _, ok := att["application_credentials_id"]; !ok && values.openstackApplicationCredentialsID != nil {
 			att["application_credentials_id"] = values.openstackApplicationCredentialsID
 		}
 		if values.openstackApplicationCredentialsSecret != nil {
@@ -288,6 +346,81 @@ func flattenAzureSpec(in *models.AzureCloudSpec) []interface{} {
 	return []interface{}{att}
 }
 
+func flattenHetznerSpec(values *models.HetznerCloudSpec, in *models.HetznerCloudSpec) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	att := make(map[string]interface{})
+
+	if in.Network != "" {
+		att["network"] = in.Network
+	}
+
+	// API does not return token, so we just preserve value used for creation
+	if values != nil {
+		if _, ok := att["network"]; !ok && values.Network != "" {
+			att["network"] = values.Network
+		}
+		if values.Token != "" {
+			att["token"] = values.Token
+		}
+	}
+
+	return []interface{}{att}
+}
+
+func flattenDigitaloceanSpec(values *models.DigitaloceanCloudSpec, in *models.DigitaloceanCloudSpec) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	att := make(map[string]interface{})
+
+	// API does not return token, so we just preserve value used for creation
+	if values != nil && values.Token != "" {
+		att["token"] = values.Token
+	}
+
+	return []interface{}{att}
+}
+
+func flattenVSphereSpec(values *models.VSphereCloudSpec, in *models.VSphereCloudSpec) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	att := make(map[string]interface{})
+
+	if in.VMNetName != "" {
+		att["vm_net_name"] = in.VMNetName
+	}
+
+	if in.Datastore != "" {
+		att["datastore"] = in.Datastore
+	}
+
+	if in.DatastoreCluster != "" {
+		att["datastore_cluster"] = in.DatastoreCluster
+	}
+
+	if in.Folder != "" {
+		att["folder"] = in.Folder
+	}
+
+	// API does not return credentials, so we just preserve values used for creation
+	if values != nil {
+		if values.Username != "" {
+			att["username"] = values.Username
+		}
+		if values.Password != "" {
+			att["password"] = values.Password
+		}
+	}
+
+	return []interface{}{att}
+}
+
 // expanders
 
 func metakubeResourceClusterExpandSpec(p []interface{}, dcName string) *models.ClusterSpec {
@@ -330,6 +463,12 @@ func metakubeResourceClusterExpandSpec(p []interface{}, dcName string) *models.C
 		}
 	}
 
+	if v, ok := in["monitoring"]; ok {
+		if vv, ok := v.([]interface{}); ok {
+			obj.Mla = expandMonitoring(vv)
+		}
+	}
+
 	if v, ok := in["pod_security_policy"]; ok {
 		if vv, ok := v.(bool); ok {
 			obj.UsePodSecurityPolicyAdmissionPlugin = vv
@@ -342,6 +481,14 @@ func metakubeResourceClusterExpandSpec(p []interface{}, dcName string) *models.C
 		}
 	}
 
+	if v, ok := in["default_node_selector"]; ok {
+		if vv, ok := v.(map[string]interface{}); ok && len(vv) > 0 {
+			obj.PodNodeSelectorAdmissionPluginConfig = map[string]string{
+				podNodeSelectorClusterDefaultKey: expandNodeSelector(vv),
+			}
+		}
+	}
+
 	if v, ok := in["services_cidr"]; ok {
 		if vv, ok := v.(string); ok && vv != "" {
 			if obj.ClusterNetwork == nil {
@@ -447,6 +594,32 @@ func expandAuditLogging(enabled bool) *models.AuditLoggingSettings {
 	}
 }
 
+// expandNodeSelector formats selector as "key1=value1,key2=value2" sorted by key.
+func expandNodeSelector(in map[string]interface{}) string {
+	keys := make([]string, 0, len(in))
+	for k := range in {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	selector := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v, _ := in[k].(string)
+		selector = append(selector, k+"="+v)
+	}
+	return strings.Join(selector, ",")
+}
+
+func expandMonitoring(p []interface{}) *models.MLASettings {
+	if len(p) < 1 {
+		return nil
+	}
+	obj := &models.MLASettings{}
+	if in, ok := p[0].(map[string]interface{}); ok {
+		obj.MonitoringEnabled, _ = in["enabled"].(bool)
+	}
+	return obj
+}
+
 func expandClusterCloudSpec(p []interface{}, dcName string) *models.CloudSpec {
 	if len(p) < 1 {
 		return nil
@@ -477,6 +650,24 @@ func expandClusterCloudSpec(p []interface{}, dcName string) *models.CloudSpec {
 		}
 	}
 
+	if v, ok := in["hetzner"]; ok {
+		if vv, ok := v.([]interface{}); ok {
+			obj.Hetzner = expandHetznerCloudSpec(vv)
+		}
+	}
+
+	if v, ok := in["digitalocean"]; ok {
+		if vv, ok := v.([]interface{}); ok {
+			obj.Digitalocean = expandDigitaloceanCloudSpec(vv)
+		}
+	}
+
+	if v, ok := in["vsphere"]; ok {
+		if vv, ok := v.([]interface{}); ok {
+			obj.Vsphere = expandVSphereCloudSpec(vv)
+		}
+	}
+
 	return obj
 }
 
@@ -719,3 +910,105 @@ func expandAzureCloudSpec(p []interface{}) *models.AzureCloudSpec {
 
 	return obj
 }
+
+func expandHetznerCloudSpec(p []interface{}) *models.HetznerCloudSpec {
+	if len(p) < 1 {
+		return nil
+	}
+
+	obj := &models.HetznerCloudSpec{}
+
+	if p[0] == nil {
+		return obj
+	}
+
+	in := p[0].(map[string]interface{})
+
+	if v, ok := in["token"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Token = vv
+		}
+	}
+
+	if v, ok := in["network"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Network = vv
+		}
+	}
+
+	return obj
+}
+
+func expandDigitaloceanCloudSpec(p []interface{}) *models.DigitaloceanCloudSpec {
+	if len(p) < 1 {
+		return nil
+	}
+
+	obj := &models.DigitaloceanCloudSpec{}
+
+	if p[0] == nil {
+		return obj
+	}
+
+	in := p[0].(map[string]interface{})
+
+	if v, ok := in["token"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Token = vv
+		}
+	}
+
+	return obj
+}
+
+func expandVSphereCloudSpec(p []interface{}) *models.VSphereCloudSpec {
+	if len(p) < 1 {
+		return nil
+	}
+
+	obj := &models.VSphereCloudSpec{}
+
+	if p[0] == nil {
+		return obj
+	}
+
+	in := p[0].(map[string]interface{})
+
+	if v, ok := in["username"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Username = vv
+		}
+	}
+
+	if v, ok := in["password"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Password = vv
+		}
+	}
+
+	if v, ok := in["vm_net_name"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.VMNetName = vv
+		}
+	}
+
+	if v, ok := in["datastore"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Datastore = vv
+		}
+	}
+
+	if v, ok := in["datastore_cluster"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.DatastoreCluster = vv
+		}
+	}
+
+	if v, ok := in["folder"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Folder = vv
+		}
+	}
+
+	return obj
+}
@@ -23,6 +23,13 @@ func TestMetakubeClusterFlattenSpec(t *testing.T) {
 				MachineNetworks:       nil,
 				EnableUserSSHKeyAgent: true,
 				AuditLogging:          &models.AuditLoggingSettings{},
+				PodNodeSelectorAdmissionPluginConfig: map[string]string{
+					"clusterDefaultNodeSelector": "role=worker,zone=a",
+					"kube-system":                "role=system",
+				},
+				Mla: &models.MLASettings{
+					MonitoringEnabled: true,
+				},
 				Cloud: &models.CloudSpec{
 					DatacenterName: "eu-west-1",
 					Openstack:      &models.OpenstackCloudSpec{},
@@ -49,13 +56,22 @@ func TestMetakubeClusterFlattenSpec(t *testing.T) {
 							"length": "3h",
 						},
 					},
-					"audit_logging":       false,
+					"audit_logging": false,
+					"monitoring": []interface{}{
+						map[string]interface{}{
+							"enabled": true,
+						},
+					},
 					"pod_security_policy": false,
 					"pod_node_selector":   false,
-					"services_cidr":       "1.1.1.0/20",
-					"pods_cidr":           "2.2.0.0/16",
-					"domain_name":         "foocluster.local",
-					"enable_ssh_agent":    true,
+					"default_node_selector": map[string]interface{}{
+						"role": "worker",
+						"zone": "a",
+					},
+					"services_cidr":    "1.1.1.0/20",
+					"pods_cidr":        "2.2.0.0/16",
+					"domain_name":      "foocluster.local",
+					"enable_ssh_agent": true,
 					"cloud": []interface{}{
 						map[string]interface{}{
 							"openstack": []interface{}{map[string]interface{}{}},
@@ -96,6 +112,31 @@ func TestMetakubeClusterFlattenSpec(t *testing.T) {
 	}
 }
 
+func TestMetakubeResourceClusterMonitoringRoundTrip(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		input := []interface{}{
+			map[string]interface{}{
+				"enabled": enabled,
+			},
+		}
+		// API may return disabled monitoring as empty settings or omit them.
+		for _, mla := range []*models.MLASettings{expandMonitoring(input), nil} {
+			if mla == nil && enabled {
+				continue
+			}
+			output := metakubeResourceClusterFlattenSpec(clusterPreserveValues{monitoringConfigured: true}, &models.ClusterSpec{Mla: mla})
+			if diff := cmp.Diff(input, output[0].(map[string]interface{})["monitoring"]); diff != "" {
+				t.Fatalf("Unexpected monitoring with enabled=%v: mismatch (-want +got):\n%s", enabled, diff)
+			}
+		}
+	}
+
+	output := metakubeResourceClusterFlattenSpec(clusterPreserveValues{}, &models.ClusterSpec{Mla: &models.MLASettings{}})
+	if v, ok := output[0].(map[string]interface{})["monitoring"]; ok {
+		t.Fatalf("Unexpected monitoring when not configured: %v", v)
+	}
+}
+
 func TestFlattenClusterCloudSpec(t *testing.T) {
 	cases := []struct {
 		Input          *models.CloudSpec
@@ -278,6 +319,35 @@ func TestFlattenOpenstackCloudSpec(t *testing.T) {
 				},
 			},
 		},
+		{
+			&models.OpenstackCloudSpec{
+				ApplicationCredentialID: "id",
+				Network:                 "Network",
+			},
+			clusterOpenstackPreservedValues{},
+			[]interface{}{
+				map[string]interface{}{
+					"application_credentials_id": "id",
+					"network":                    "Network",
+				},
+			},
+		},
+		{
+			&models.OpenstackCloudSpec{
+				Username: "Username",
+				Tenant:   "Tenant",
+			},
+			clusterOpenstackPreservedValues{
+				openstackPassword: "Password",
+			},
+			[]interface{}{
+				map[string]interface{}{
+					"username": "Username",
+					"password": "Password",
+					"tenant":   "Tenant",
+				},
+			},
+		},
 		{
 			&models.OpenstackCloudSpec{},
 			clusterOpenstackPreservedValues{},
@@ -353,6 +423,135 @@ func TestFlattenAzureCloudSpec(t *testing.T) {
 	}
 }
 
+func TestFlattenHetznerCloudSpec(t *testing.T) {
+	cases := []struct {
+		PreservedValues *models.HetznerCloudSpec
+		Input           *models.HetznerCloudSpec
+		ExpectedOutput  []interface{}
+	}{
+		{
+			&models.HetznerCloudSpec{
+				Token: "Token",
+			},
+			&models.HetznerCloudSpec{
+				Network: "Network",
+			},
+			[]interface{}{
+				map[string]interface{}{
+					"token":   "Token",
+					"network": "Network",
+				},
+			},
+		},
+		{
+			nil,
+			&models.HetznerCloudSpec{},
+			[]interface{}{
+				map[string]interface{}{},
+			},
+		},
+		{
+			nil,
+			nil,
+			[]interface{}{},
+		},
+	}
+
+	for _, tc := range cases {
+		output := flattenHetznerSpec(tc.PreservedValues, tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestFlattenDigitaloceanCloudSpec(t *testing.T) {
+	cases := []struct {
+		PreservedValues *models.DigitaloceanCloudSpec
+		Input           *models.DigitaloceanCloudSpec
+		ExpectedOutput  []interface{}
+	}{
+		{
+			&models.DigitaloceanCloudSpec{
+				Token: "Token",
+			},
+			&models.DigitaloceanCloudSpec{},
+			[]interface{}{
+				map[string]interface{}{
+					"token": "Token",
+				},
+			},
+		},
+		{
+			nil,
+			&models.DigitaloceanCloudSpec{},
+			[]interface{}{
+				map[string]interface{}{},
+			},
+		},
+		{
+			nil,
+			nil,
+			[]interface{}{},
+		},
+	}
+
+	for _, tc := range cases {
+		output := flattenDigitaloceanSpec(tc.PreservedValues, tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestFlattenVSphereCloudSpec(t *testing.T) {
+	cases := []struct {
+		PreservedValues *models.VSphereCloudSpec
+		Input           *models.VSphereCloudSpec
+		ExpectedOutput  []interface{}
+	}{
+		{
+			&models.VSphereCloudSpec{
+				Username: "Username",
+				Password: "Password",
+			},
+			&models.VSphereCloudSpec{
+				VMNetName: "VMNetName",
+				Datastore: "Datastore",
+				Folder:    "Folder",
+			},
+			[]interface{}{
+				map[string]interface{}{
+					"username":    "Username",
+					"password":    "Password",
+					"vm_net_name": "VMNetName",
+					"datastore":   "Datastore",
+					"folder":      "Folder",
+				},
+			},
+		},
+		{
+			nil,
+			&models.VSphereCloudSpec{},
+			[]interface{}{
+				map[string]interface{}{},
+			},
+		},
+		{
+			nil,
+			nil,
+			[]interface{}{},
+		},
+	}
+
+	for _, tc := range cases {
+		output := flattenVSphereSpec(tc.PreservedValues, tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
 func TestFlattenMachineNetwork(t *testing.T) {
 	cases := []struct {
 		Input          []*models.MachineNetworkingConfig
@@ -414,13 +613,22 @@ func TestExpandClusterSpec(t *testing.T) {
 							"length": "3h",
 						},
 					},
-					"machine_networks":    []interface{}{},
-					"audit_logging":       false,
+					"machine_networks": []interface{}{},
+					"audit_logging":    false,
+					"monitoring": []interface{}{
+						map[string]interface{}{
+							"enabled": true,
+						},
+					},
 					"pod_security_policy": true,
 					"pod_node_selector":   true,
-					"services_cidr":       "1.1.1.0/20",
-					"pods_cidr":           "2.2.0.0/16",
-					"domain_name":         "foocluster.local",
+					"default_node_selector": map[string]interface{}{
+						"zone": "a",
+						"role": "worker",
+					},
+					"services_cidr": "1.1.1.0/20",
+					"pods_cidr":     "2.2.0.0/16",
+					"domain_name":   "foocluster.local",
 					"cloud": []interface{}{
 						map[string]interface{}{
 							"openstack": []interface{}{
@@ -443,8 +651,12 @@ func TestExpandClusterSpec(t *testing.T) {
 				},
 				MachineNetworks:                     nil,
 				AuditLogging:                        &models.AuditLoggingSettings{},
+				Mla:                                 &models.MLASettings{MonitoringEnabled: true},
 				UsePodSecurityPolicyAdmissionPlugin: true,
 				UsePodNodeSelectorAdmissionPlugin:   true,
+				PodNodeSelectorAdmissionPluginConfig: map[string]string{
+					"clusterDefaultNodeSelector": "role=worker,zone=a",
+				},
 				ClusterNetwork: &models.ClusterNetworkingConfig{
 					Services: &models.NetworkRanges{
 						CIDRBlocks: []string{"1.1.1.0/20"},
@@ -677,6 +889,121 @@ func TestExpandAzureCloudSpec(t *testing.T) {
 	}
 }
 
+func TestExpandHetznerCloudSpec(t *testing.T) {
+	cases := []struct {
+		Input          []interface{}
+		ExpectedOutput *models.HetznerCloudSpec
+	}{
+		{
+			[]interface{}{
+				map[string]interface{}{
+					"token":   "Token",
+					"network": "Network",
+				},
+			},
+			&models.HetznerCloudSpec{
+				Token:   "Token",
+				Network: "Network",
+			},
+		},
+		{
+			[]interface{}{
+				map[string]interface{}{},
+			},
+			&models.HetznerCloudSpec{},
+		},
+		{
+			[]interface{}{},
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		output := expandHetznerCloudSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from expander: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestExpandDigitaloceanCloudSpec(t *testing.T) {
+	cases := []struct {
+		Input          []interface{}
+		ExpectedOutput *models.DigitaloceanCloudSpec
+	}{
+		{
+			[]interface{}{
+				map[string]interface{}{
+					"token": "Token",
+				},
+			},
+			&models.DigitaloceanCloudSpec{
+				Token: "Token",
+			},
+		},
+		{
+			[]interface{}{
+				map[string]interface{}{},
+			},
+			&models.DigitaloceanCloudSpec{},
+		},
+		{
+			[]interface{}{},
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		output := expandDigitaloceanCloudSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from expander: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestExpandVSphereCloudSpec(t *testing.T) {
+	cases := []struct {
+		Input          []interface{}
+		ExpectedOutput *models.VSphereCloudSpec
+	}{
+		{
+			[]interface{}{
+				map[string]interface{}{
+					"username":          "Username",
+					"password":          "Password",
+					"vm_net_name":       "VMNetName",
+					"datastore_cluster": "DatastoreCluster",
+					"folder":            "Folder",
+				},
+			},
+			&models.VSphereCloudSpec{
+				Username:         "Username",
+				Password:         "Password",
+				VMNetName:        "VMNetName",
+				DatastoreCluster: "DatastoreCluster",
+				Folder:           "Folder",
+			},
+		},
+		{
+			[]interface{}{
+				map[string]interface{}{},
+			},
+			&models.VSphereCloudSpec{},
+		},
+		{
+			[]interface{}{},
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		output := expandVSphereCloudSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from expander: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
 func TestExpandMachineNetwork(t *testing.T) {
 	cases := []struct {
 		Input          []interface{}
@@ -1,7 +1,10 @@
 package metakube
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"strconv"
@@ -41,6 +44,9 @@ func testSweepClusters(region string) error {
 			WithDeleteVolumes(&t).
 			WithClusterID(rec.ID)
 		if _, err := meta.client.Project.DeleteClusterV2(p, meta.auth); err != nil {
+			if isNotFound(err) {
+				continue
+			}
 			return fmt.Errorf("delete cluster: %v", stringifyResponseError(err))
 		}
 	}
@@ -107,6 +113,7 @@ func TestAccMetakubeCluster_Openstack_Basic(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resourceName, "spec.0.cloud.0.openstack.0.subnet_id"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.cloud.0.openstack.0.subnet_cidr", "192.168.2.0/24"),
 					resource.TestCheckResourceAttrSet(resourceName, "kube_config"),
+					resource.TestCheckResourceAttrSet(resourceName, "endpoint"),
 					// Test spec.0.machine_networks value
 					testResourceInstanceState(resourceName, func(is *terraform.InstanceState) error {
 						n, err := strconv.Atoi(is.Attributes["spec.0.machine_networks.#"])
@@ -291,6 +298,22 @@ func TestAccMetakubeCluster_Openstack_ApplicationCredentials(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "spec.0.cloud.0.openstack.0.application_credentials_secret", data.OpenstackApplicationCredentialSecret),
 				),
 			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"spec.0.cloud.0.openstack.0.application_credentials_secret",
+				},
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					for _, s := range states {
+						if v := s.Attributes["spec.0.cloud.0.openstack.0.username"]; v != "" {
+							return fmt.Errorf("expected username to be empty for cluster with application credentials, got %s", v)
+						}
+					}
+					return nil
+				},
+			},
 		},
 	})
 }
@@ -736,6 +759,43 @@ func testAccCheckMetaKubeClusterHasSSHKey(cluster, sshkey *string) resource.Test
 	}
 }
 
+func TestAssignSSHKeysToClusterRetriesConflict(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v2/projects/project-id/clusters/cluster-id/sshkeys/key-id" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"code":409,"message":"conflict"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"key-id"}`))
+	}))
+	defer server.Close()
+
+	client, diags := newClient(server.URL, defaultAPITimeout, nil)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	auth, diags := newAuth("token", "", "")
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	k := &metakubeProviderMeta{client: client, auth: auth}
+
+	if err := assignSSHKeysToCluster(context.Background(), "project-id", "cluster-id", []string{"key-id"}, k); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("want 2 assign requests, got %d", calls)
+	}
+}
+
 func TestAccMetakubeCluster_Azure_Basic(t *testing.T) {
 	var cluster models.Cluster
 	resourceName := "metakube_cluster.acctest_cluster"
@@ -827,6 +887,74 @@ resource "metakube_cluster" "acctest_cluster" {
 	}
 }`)
 
+func TestAccMetakubeCluster_Hetzner_Basic(t *testing.T) {
+	var cluster models.Cluster
+	resourceName := "metakube_cluster.acctest_cluster"
+
+	data := &clusterHetznerBasicData{
+		Name:           makeRandomName(),
+		ProjectID:      os.Getenv(testEnvProjectID),
+		Token:          os.Getenv(testEnvHetznerToken),
+		DatacenterName: os.Getenv(testEnvHetznerNodeDC),
+		Version:        os.Getenv(testEnvK8sVersion),
+	}
+	var config strings.Builder
+	if err := testAccCheckMetaKubeClusterHetznerBasic.Execute(&config, data); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheckForHetzner(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetaKubeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config.String(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeClusterExists(&cluster),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.cloud.0.hetzner.0.token", data.Token),
+				),
+			},
+			{
+				Config:   config.String(),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"spec.0.cloud.0.hetzner.0.token",
+				},
+			},
+		},
+	})
+}
+
+type clusterHetznerBasicData struct {
+	Name           string
+	DatacenterName string
+	ProjectID      string
+	Version        string
+	Token          string
+}
+
+var testAccCheckMetaKubeClusterHetznerBasic = mustParseTemplate("clusterHetznerBasic", `
+resource "metakube_cluster" "acctest_cluster" {
+	name = "{{ .Name }}"
+	dc_name = "{{ .DatacenterName }}"
+	project_id = "{{ .ProjectID }}"
+
+	spec {
+		version = "{{ .Version }}"
+		cloud {
+			hetzner {
+				token = "{{ .Token }}"
+			}
+		}
+	}
+}`)
+
 func TestAccMetakubeCluster_AWS_Basic(t *testing.T) {
 	var cluster models.Cluster
 	resourceName := "metakube_cluster.acctest_cluster"
@@ -955,3 +1083,52 @@ func testAccCheckMetaKubeClusterExists(cluster *models.Cluster) resource.TestChe
 		return nil
 	}
 }
+
+func TestMetakubeClusterUnhealthyComponents(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          *models.ClusterHealth
+		ExpectedOutput []string
+	}{
+		{
+			"all up",
+			&models.ClusterHealth{
+				Apiserver:                    1,
+				CloudProviderInfrastructure:  1,
+				Controller:                   1,
+				Etcd:                         1,
+				MachineController:            1,
+				Scheduler:                    1,
+				UserClusterControllerManager: 1,
+			},
+			nil,
+		},
+		{
+			"machine controller and infrastructure down",
+			&models.ClusterHealth{
+				Apiserver:                    1,
+				CloudProviderInfrastructure:  0,
+				Controller:                   1,
+				Etcd:                         1,
+				MachineController:            2,
+				Scheduler:                    1,
+				UserClusterControllerManager: 1,
+			},
+			[]string{"cloudProviderInfrastructure", "machineController"},
+		},
+		{
+			"no health",
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			output := metakubeClusterUnhealthyComponents(tc.Input)
+			if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+				t.Fatalf("Unexpected output: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
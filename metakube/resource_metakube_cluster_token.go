@@ -0,0 +1,153 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/project"
+)
+
+func metakubeResourceClusterToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceClusterTokenCreate,
+		ReadContext:   metakubeResourceClusterTokenRead,
+		DeleteContext: metakubeResourceClusterTokenDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project the cluster belongs to",
+			},
+
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Cluster to rotate the admin token of",
+			},
+
+			"keepers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values, changing any of them rotates the token",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Admin token of the cluster",
+			},
+
+			"kube_config": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Admin kubeconfig of the cluster with the rotated token",
+			},
+
+			"rotated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the rotation",
+			},
+		},
+	}
+}
+
+func metakubeResourceClusterTokenCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+
+	// Without the current token the new one can't be told apart from it, so don't revoke it.
+	raw, err := metakubeClusterUpdateKubeconfig(ctx, k, projectID, clusterID)
+	if err != nil {
+		return diag.Errorf("unable to get current admin token of cluster '%s': %v", clusterID, err)
+	}
+	_, _, previous, err := metakubeParseKubeconfig(raw)
+	if err != nil {
+		return diag.Errorf("unable to parse kubeconfig of cluster '%s': %v", clusterID, err)
+	}
+	if previous == "" {
+		return diag.Errorf("unable to get current admin token of cluster '%s', kubeconfig has no token", clusterID)
+	}
+
+	p := project.NewRevokeClusterAdminTokenV2Params().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithClusterID(clusterID)
+	if _, err := k.client.Project.RevokeClusterAdminTokenV2(p, k.auth); err != nil {
+		return diag.Errorf("unable to rotate admin token of cluster '%s': %s", clusterID, stringifyResponseError(err))
+	}
+	rotatedAt := time.Now().UTC()
+
+	// The new token is available in the kubeconfig once the cluster controller has reconciled it.
+	var token string
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		var err error
+		raw, err = metakubeClusterUpdateKubeconfig(ctx, k, projectID, clusterID)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+		_, _, token, err = metakubeParseKubeconfig(raw)
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("unable to parse kubeconfig: %v", err))
+		}
+		if token == "" || token == previous {
+			return resource.RetryableError(fmt.Errorf("waiting for admin token of cluster '%s' to be rotated", clusterID))
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d", clusterID, rotatedAt.Unix()))
+	_ = d.Set("token", token)
+	_ = d.Set("kube_config", raw)
+	_ = d.Set("rotated_at", rotatedAt.Format(time.RFC3339))
+
+	return metakubeResourceClusterTokenRead(ctx, d, m)
+}
+
+func metakubeResourceClusterTokenRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+
+	_, ok, err := metakubeGetCluster(ctx, projectID, clusterID, k)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !ok {
+		k.log.Infof("removing cluster token '%s' from terraform state file, could not find the cluster", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func metakubeResourceClusterTokenDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	// Tokens can't be deleted, the current token stays valid until it is rotated again.
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,118 @@
+package metakube
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccMetakubeClusterToken_Rotate(t *testing.T) {
+	resourceName := "metakube_cluster_token.acctest"
+	params := &testAccCheckMetaKubeClusterTokenBasicParams{
+		ClusterName:                          makeRandomName(),
+		DatacenterName:                       os.Getenv(testEnvOpenstackNodeDC),
+		ProjectID:                            os.Getenv(testEnvProjectID),
+		Version:                              os.Getenv(testEnvK8sVersion),
+		OpenstackApplicationCredentialID:     os.Getenv(testEnvOpenstackApplicationCredentialsID),
+		OpenstackApplicationCredentialSecret: os.Getenv(testEnvOpenstackApplicationCredentialsSecret),
+
+		Keeper: "1",
+	}
+	updatedParams := *params
+	updatedParams.Keeper = "2"
+	var token string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetaKubeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckMetaKubeClusterTokenBasicConfig(t, params),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeClusterTokenValue(resourceName, &token),
+					resource.TestCheckResourceAttrSet(resourceName, "kube_config"),
+					resource.TestCheckResourceAttrSet(resourceName, "rotated_at"),
+				),
+			},
+			{
+				Config: testAccCheckMetaKubeClusterTokenBasicConfig(t, &updatedParams),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						previous := token
+						if err := testAccCheckMetaKubeClusterTokenValue(resourceName, &token)(s); err != nil {
+							return err
+						}
+						if token == previous {
+							return fmt.Errorf("token was not rotated")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+type testAccCheckMetaKubeClusterTokenBasicParams struct {
+	ClusterName                          string
+	DatacenterName                       string
+	ProjectID                            string
+	Version                              string
+	OpenstackApplicationCredentialID     string
+	OpenstackApplicationCredentialSecret string
+
+	Keeper string
+}
+
+func testAccCheckMetaKubeClusterTokenBasicConfig(t *testing.T, params *testAccCheckMetaKubeClusterTokenBasicParams) string {
+	t.Helper()
+
+	var result strings.Builder
+	err := mustParseTemplate("cluster token test template", `
+resource "metakube_cluster" "acctest" {
+	name = "{{ .ClusterName }}"
+	dc_name = "{{ .DatacenterName }}"
+	project_id = "{{ .ProjectID }}"
+
+	spec {
+		version = "{{ .Version }}"
+		cloud {
+			openstack {
+				application_credentials_id="{{ .OpenstackApplicationCredentialID }}"
+				application_credentials_secret="{{ .OpenstackApplicationCredentialSecret }}"
+			}
+		}
+	}
+}
+
+resource "metakube_cluster_token" "acctest" {
+	project_id = "{{ .ProjectID }}"
+	cluster_id = metakube_cluster.acctest.id
+	keepers = {
+		rotation = "{{ .Keeper }}"
+	}
+}
+`).Execute(&result, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.String()
+}
+
+func testAccCheckMetaKubeClusterTokenValue(n string, token *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		if rs.Primary.Attributes["token"] == "" {
+			return fmt.Errorf("No token is set")
+		}
+		*token = rs.Primary.Attributes["token"]
+		return nil
+	}
+}
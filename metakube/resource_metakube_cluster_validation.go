@@ -3,9 +3,13 @@ package metakube
 import (
 	"context"
 	"fmt"
+	"net"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/syseleven/go-metakube/client/project"
+	"golang.org/x/mod/semver"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -73,15 +77,160 @@ func toStrPtrOrNil(v interface{}) *string {
 
 func metakubeResourceClusterValidateClusterFields(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) diag.Diagnostics {
 	ret := metakubeResourceValidateVersionExistence(ctx, d, k)
+	ret = append(ret, metakubeResourceClusterValidateDatacenterProvider(ctx, d, k)...)
+	ret = append(ret, metakubeResourceClusterValidateDefaultNodeSelector(d)...)
+	ret = append(ret, metakubeResourceClusterValidateMachineNetworks(d)...)
+	ret = append(ret, metakubeResourceClusterValidateSSHKeys(ctx, d, k)...)
 	if _, ok := d.GetOk("spec.0.cloud.0.openstack.0"); !ok {
 		return ret
 	}
 	ret = append(ret, metakubeResourceClusterValidateFloatingIPPool(ctx, d, k)...)
 	ret = append(ret, metakubeResourceClusterValidateOpenstackNetwork(ctx, d, k)...)
-	ret = append(ret, metakubeResourceClusterValidateAccessCredentialsSet(d)...)
+	ret = append(ret, metakubeResourceClusterValidateAccessCredentialsSet(d, k)...)
 	return append(ret, diagnoseOpenstackSubnetWithIDExistsIfSet(ctx, d, k)...)
 }
 
+func metakubeResourceClusterValidateDefaultNodeSelector(d *schema.ResourceData) diag.Diagnostics {
+	if v, ok := d.GetOk("spec.0.default_node_selector"); !ok || len(v.(map[string]interface{})) == 0 || d.Get("spec.0.pod_node_selector").(bool) {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       "default_node_selector requires PodNodeSelector admission plugin, please set pod_node_selector to true",
+		AttributePath: cty.GetAttrPath("spec").IndexInt(0).GetAttr("default_node_selector"),
+	}}
+}
+
+func metakubeResourceClusterValidateSSHKeys(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) diag.Diagnostics {
+	keys := metakubeResourceClusterSSHKeys(d)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	projectID := d.Get("project_id").(string)
+	p := project.NewListSSHKeysParams().WithContext(ctx).WithProjectID(projectID)
+	r, err := k.client.Project.ListSSHKeys(p, k.auth)
+	if err != nil {
+		k.log.Debugf("skip sshkeys validation, unable to list project sshkeys: %s", stringifyResponseError(err))
+		return nil
+	}
+	available := make(map[string]bool, len(r.Payload))
+	var ids []string
+	for _, v := range r.Payload {
+		available[v.ID] = true
+		ids = append(ids, v.ID)
+	}
+
+	var ret diag.Diagnostics
+	for _, id := range keys {
+		if available[id] {
+			continue
+		}
+		ret = append(ret, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("SSH key '%s' not found in project '%s'", id, projectID),
+			Detail:        fmt.Sprintf("available sshkeys: %s", strings.Join(ids, ", ")),
+			AttributePath: cty.GetAttrPath("sshkeys"),
+		})
+	}
+	return ret
+}
+
+func metakubeResourceClusterValidateMachineNetworks(d *schema.ResourceData) diag.Diagnostics {
+	networks := d.Get("spec.0.machine_networks").([]interface{})
+	if len(networks) == 0 {
+		return nil
+	}
+
+	path := cty.GetAttrPath("spec").IndexInt(0).GetAttr("machine_networks")
+	if d.Get("spec.0.cloud.0.vsphere.#").(int) == 0 {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "machine_networks are only supported for vsphere clusters",
+			AttributePath: path,
+		}}
+	}
+
+	var ret diag.Diagnostics
+	for i, v := range networks {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cidr, _ := m["cidr"].(string)
+		gateway, _ := m["gateway"].(string)
+		if cidr == "" || gateway == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if ip := net.ParseIP(gateway); ip != nil && !network.Contains(ip) {
+			ret = append(ret, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("gateway '%s' is not in network '%s'", gateway, cidr),
+				AttributePath: path.IndexInt(i).GetAttr("gateway"),
+			})
+		}
+	}
+	return ret
+}
+
+func metakubeResourceClusterValidateDatacenterProvider(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) diag.Diagnostics {
+	var clusterProvider string
+	for _, p := range metakubeNodeDeploymentCloudProviders {
+		if d.Get(fmt.Sprintf("spec.0.cloud.0.%s.#", p)).(int) == 1 {
+			clusterProvider = p
+		}
+	}
+	name := d.Get("dc_name").(string)
+	if clusterProvider == "" || name == "" {
+		return nil
+	}
+
+	datacenters, err := metakubeListDatacenters(ctx, k)
+	if err != nil {
+		// Reported when looking up the datacenter.
+		return nil
+	}
+	for _, dc := range datacenters {
+		if dc == nil || dc.Metadata == nil || dc.Spec == nil || dc.Metadata.Name != name {
+			continue
+		}
+		if dcProvider := metakubeDatacenterCloudProvider(dc); dcProvider != "" && dcProvider != clusterProvider {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Datacenter '%s' is for %s clusters, but %s cloud is configured", name, dcProvider, clusterProvider),
+				AttributePath: cty.GetAttrPath("dc_name"),
+			}}
+		}
+	}
+	return nil
+}
+
+func metakubeDatacenterCloudProvider(dc *models.Datacenter) string {
+	if dc == nil || dc.Spec == nil {
+		return ""
+	}
+	switch {
+	case dc.Spec.Aws != nil:
+		return "aws"
+	case dc.Spec.Openstack != nil:
+		return "openstack"
+	case dc.Spec.Azure != nil:
+		return "azure"
+	case dc.Spec.Hetzner != nil:
+		return "hetzner"
+	case dc.Spec.Digitalocean != nil:
+		return "digitalocean"
+	case dc.Spec.Vsphere != nil:
+		return "vsphere"
+	default:
+		return ""
+	}
+}
+
 func metakubeResourceClusterValidateVersionUpgrade(ctx context.Context, projectID, newVersion string, cluster *models.Cluster, k *metakubeProviderMeta) diag.Diagnostics {
 	p := project.NewGetClusterUpgradesV2Params().
 		WithContext(ctx).
@@ -109,18 +258,12 @@ func metakubeResourceClusterValidateVersionUpgrade(ctx context.Context, projectI
 
 func metakubeResourceValidateVersionExistence(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) diag.Diagnostics {
 	version := d.Get("spec.0.version").(string)
-	p := versions.NewGetMasterVersionsParams().WithContext(ctx)
-	r, err := k.client.Versions.GetMasterVersions(p, k.auth)
+	resolved, available, err := metakubeResourceClusterResolveVersion(ctx, k, version)
 	if err != nil {
-		return diag.Errorf("%s", stringifyResponseError(err))
+		return diag.Errorf("%s", err)
 	}
-
-	available := make([]string, 0)
-	for _, v := range r.Payload {
-		available = append(available, v.Version.(string))
-		if v.Version.(string) == version {
-			return nil
-		}
+	if resolved != "" {
+		return nil
 	}
 
 	return diag.Diagnostics{{
@@ -131,6 +274,74 @@ func metakubeResourceValidateVersionExistence(ctx context.Context, d *schema.Res
 	}}
 }
 
+// metakubeResourceClusterResolveVersion returns the available version matching the given one.
+// Partial version, like "1.28", resolves to the newest available patch version.
+func metakubeResourceClusterResolveVersion(ctx context.Context, k *metakubeProviderMeta, version string) (string, []string, error) {
+	all, err := metakubeGetMasterVersions(ctx, k)
+	if err != nil {
+		return "", nil, err
+	}
+
+	available := make([]string, 0)
+	for _, v := range all {
+		if v != nil {
+			available = append(available, v.Version.(string))
+		}
+	}
+	return metakubeResourceClusterMatchVersion(available, version), available, nil
+}
+
+// metakubeMasterVersionsCache keeps versions supported by MetaKube for the lifetime of the provider,
+// so validating many clusters and reading versions data sources doesn't list them repeatedly.
+type metakubeMasterVersionsCache struct {
+	mu       sync.Mutex
+	versions []*models.MasterVersion
+}
+
+func metakubeGetMasterVersions(ctx context.Context, k *metakubeProviderMeta) ([]*models.MasterVersion, error) {
+	if c := k.masterVersions; c != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.versions != nil {
+			return c.versions, nil
+		}
+	}
+
+	p := versions.NewGetMasterVersionsParams().WithContext(ctx)
+	r, err := k.client.Versions.GetMasterVersions(p, k.auth)
+	if err != nil {
+		return nil, fmt.Errorf("%s", stringifyResponseError(err))
+	}
+	if k.masterVersions != nil && r.Payload != nil {
+		k.masterVersions.versions = r.Payload
+	}
+	return r.Payload, nil
+}
+
+func metakubeResourceClusterMatchVersion(available []string, version string) string {
+	var ret string
+	for _, v := range available {
+		if v == version {
+			return v
+		}
+		if metakubeResourceClusterIsPartialVersion(version) && strings.HasPrefix(v, version+".") {
+			if ret == "" || semver.Compare("v"+v, "v"+ret) > 0 {
+				ret = v
+			}
+		}
+	}
+	return ret
+}
+
+func metakubeResourceClusterIsPartialVersion(version string) bool {
+	return strings.Count(version, ".") < 2
+}
+
+// metakubeResourceClusterIsPartialVersionOf checks if partial version, like "1.28", resolves to the given version.
+func metakubeResourceClusterIsPartialVersionOf(partial, version string) bool {
+	return metakubeResourceClusterIsPartialVersion(partial) && strings.HasPrefix(version, partial+".")
+}
+
 func metakubeResourceClusterValidateFloatingIPPool(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) diag.Diagnostics {
 	nets, err := validateOpenstackNetworkExistsIfSet(ctx, d, k, "spec.0.cloud.0.openstack.0.floating_ip_pool", true)
 	if err != nil {
@@ -154,7 +365,7 @@ func metakubeResourceClusterValidateFloatingIPPool(ctx context.Context, d *schem
 	return nil
 }
 
-func metakubeResourceClusterValidateAccessCredentialsSet(d *schema.ResourceData) diag.Diagnostics {
+func metakubeResourceClusterValidateAccessCredentialsSet(d *schema.ResourceData, k *metakubeProviderMeta) diag.Diagnostics {
 	data := newOpenstackValidationData(d)
 	username := data.username != nil && *data.username != ""
 	password := data.password != nil && *data.password != ""
@@ -205,6 +416,15 @@ func metakubeResourceClusterValidateAccessCredentialsSet(d *schema.ResourceData)
 		}}
 	}
 
+	if username && !k.suppressPasswordAuthWarning {
+		return diag.Diagnostics{{
+			Severity:      diag.Warning,
+			Summary:       "Username and password authentication is deprecated, please use application_credentials_id and application_credentials_secret instead",
+			Detail:        "Application credentials can be created in the OpenStack dashboard and revoked without changing the password. Set provider suppress_password_auth_warning to hide this warning.",
+			AttributePath: cty.GetAttrPath("spec").IndexInt(0).GetAttr("cloud").IndexInt(0).GetAttr("openstack").IndexInt(0).GetAttr("username"),
+		}}
+	}
+
 	return nil
 }
 
@@ -244,10 +464,18 @@ func validateOpenstackNetworkExistsIfSet(ctx context.Context, d *schema.Resource
 
 func diagnoseOpenstackSubnetWithIDExistsIfSet(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) diag.Diagnostics {
 	data := newOpenstackValidationData(d)
-	if data.network == nil || data.subnetID == nil {
+	if data.subnetID == nil || *data.subnetID == "" {
 		return nil
 	}
-	network, _, err := getNetwork(ctx, k, data, *data.network, true)
+	if data.network == nil || *data.network == "" {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "network must be set when subnet_id is set",
+			AttributePath: cty.GetAttrPath("spec").IndexInt(0).GetAttr("cloud").IndexInt(0).GetAttr("openstack").IndexInt(0).GetAttr("subnet_id"),
+			Detail:        "Subnet can only be found in the network it belongs to.",
+		}}
+	}
+	network, _, err := getNetwork(ctx, k, data, *data.network, false)
 	if err != nil {
 		return nil
 	}
@@ -256,6 +484,9 @@ func diagnoseOpenstackSubnetWithIDExistsIfSet(ctx context.Context, d *schema.Res
 	if ok {
 		return nil
 	}
+	if err == nil {
+		err = fmt.Errorf("subnet `%s` not found", *data.subnetID)
+	}
 	var diagnoseDetail string
 	if len(subnets) > 0 {
 		tmp := make([]string, 0)
@@ -267,7 +498,7 @@ func diagnoseOpenstackSubnetWithIDExistsIfSet(ctx context.Context, d *schema.Res
 	return diag.Diagnostics{{
 		Severity:      diag.Error,
 		Summary:       fmt.Sprintf("invalid value: %v", err),
-		AttributePath: cty.GetAttrPath("spec").IndexInt(0).GetAttr("cloud").IndexInt(0).GetAttr("openstack").IndexInt(0).GetAttr("subnetID"),
+		AttributePath: cty.GetAttrPath("spec").IndexInt(0).GetAttr("cloud").IndexInt(0).GetAttr("openstack").IndexInt(0).GetAttr("subnet_id"),
 		Detail:        diagnoseDetail,
 	}}
 }
@@ -286,9 +517,21 @@ func getNetwork(ctx context.Context, k *metakubeProviderMeta, data metakubeResou
 	return ret, res.Payload, nil
 }
 
+// openstackIDRegexp matches OpenStack resource IDs, which are UUIDs.
+var openstackIDRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+
+// findNetwork finds network by ID if given value looks like an ID, by name otherwise
+// or if no network has the ID, as names can look like IDs too.
 func findNetwork(list []*models.OpenstackNetwork, network string, external bool) *models.OpenstackNetwork {
+	if openstackIDRegexp.MatchString(network) {
+		for _, item := range list {
+			if item != nil && item.External == external && item.ID == network {
+				return item
+			}
+		}
+	}
 	for _, item := range list {
-		if item.Name == network && item.External == external {
+		if item != nil && item.External == external && item.Name == network {
 			return item
 		}
 	}
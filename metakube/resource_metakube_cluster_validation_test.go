@@ -0,0 +1,733 @@
+package metakube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func TestDiagnoseOpenstackSubnetWithIDExistsIfSet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/providers/openstack/networks":
+			w.Write([]byte(`[{"id":"network-id","name":"network","external":false}]`))
+		case "/api/v1/providers/openstack/subnets":
+			w.Write([]byte(`[{"id":"subnet-id","name":"subnet"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, diags := newClient(server.URL, defaultAPITimeout, nil)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	auth, diags := newAuth("token", "", "")
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	k := &metakubeProviderMeta{client: client, auth: auth}
+
+	cases := []struct {
+		Name                  string
+		Openstack             map[string]interface{}
+		ExpectedAttributePath cty.Path
+	}{
+		{
+			"subnet not set",
+			map[string]interface{}{},
+			nil,
+		},
+		{
+			"subnet without network",
+			map[string]interface{}{
+				"subnet_id": "subnet-id",
+			},
+			cty.GetAttrPath("spec").IndexInt(0).GetAttr("cloud").IndexInt(0).GetAttr("openstack").IndexInt(0).GetAttr("subnet_id"),
+		},
+		{
+			"subnet exists",
+			map[string]interface{}{
+				"network":   "network",
+				"subnet_id": "subnet-id",
+			},
+			nil,
+		},
+		{
+			"subnet does not exist",
+			map[string]interface{}{
+				"network":   "network",
+				"subnet_id": "unknown",
+			},
+			cty.GetAttrPath("spec").IndexInt(0).GetAttr("cloud").IndexInt(0).GetAttr("openstack").IndexInt(0).GetAttr("subnet_id"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, metakubeResourceCluster().Schema, map[string]interface{}{
+				"dc_name": "dc",
+				"spec": []interface{}{
+					map[string]interface{}{
+						"cloud": []interface{}{
+							map[string]interface{}{
+								"openstack": []interface{}{tc.Openstack},
+							},
+						},
+					},
+				},
+			})
+
+			ret := diagnoseOpenstackSubnetWithIDExistsIfSet(context.Background(), d, k)
+			if tc.ExpectedAttributePath == nil {
+				if len(ret) != 0 {
+					t.Fatalf("Unexpected diagnostics: %v", ret)
+				}
+				return
+			}
+			if len(ret) != 1 {
+				t.Fatalf("Expected one diagnostic, got %v", ret)
+			}
+			if !tc.ExpectedAttributePath.Equals(ret[0].AttributePath) {
+				t.Fatalf("Unexpected attribute path: want %#v, got %#v", tc.ExpectedAttributePath, ret[0].AttributePath)
+			}
+		})
+	}
+}
+
+func TestFindNetwork(t *testing.T) {
+	networks := []*models.OpenstackNetwork{
+		{
+			ID:       "6a1b3c4d-0000-4000-8000-000000000001",
+			Name:     "ext-net",
+			External: true,
+		},
+		{
+			ID:       "6a1b3c4d-0000-4000-8000-000000000002",
+			Name:     "ext-net",
+			External: true,
+		},
+		{
+			ID:   "6a1b3c4d-0000-4000-8000-000000000003",
+			Name: "internal",
+		},
+		{
+			ID:   "6a1b3c4d-0000-4000-8000-000000000004",
+			Name: "6a1b3c4d-0000-4000-8000-00000000000a",
+		},
+	}
+
+	cases := []struct {
+		Network        string
+		External       bool
+		ExpectedOutput *models.OpenstackNetwork
+	}{
+		{
+			"ext-net",
+			true,
+			networks[0],
+		},
+		{
+			"6a1b3c4d-0000-4000-8000-000000000002",
+			true,
+			networks[1],
+		},
+		{
+			"6a1b3c4d-0000-4000-8000-000000000003",
+			true,
+			nil,
+		},
+		{
+			"6a1b3c4d-0000-4000-8000-000000000003",
+			false,
+			networks[2],
+		},
+		{
+			"6a1b3c4d-0000-4000-8000-00000000000a",
+			false,
+			networks[3],
+		},
+		{
+			"unknown",
+			false,
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		output := findNetwork(networks, tc.Network, tc.External)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestMetakubeResourceClusterValidateDatacenterProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/dc":
+			w.Write([]byte(`[{"metadata":{"name":"dbl1"},"spec":{"seed":"europe","openstack":{}}},{"metadata":{"name":"aws-eu-central-1a"},"spec":{"seed":"europe","aws":{}}},{"spec":{"seed":"europe","aws":{}}},{"metadata":{"name":"no-spec"}},null]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, diags := newClient(server.URL, defaultAPITimeout, nil)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	auth, diags := newAuth("token", "", "")
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	k := &metakubeProviderMeta{client: client, auth: auth}
+
+	cases := []struct {
+		Name           string
+		DatacenterName string
+		ExpectedError  bool
+	}{
+		{
+			"matching provider",
+			"dbl1",
+			false,
+		},
+		{
+			"different provider",
+			"aws-eu-central-1a",
+			true,
+		},
+		{
+			"unknown datacenter",
+			"unknown",
+			false,
+		},
+		{
+			"datacenter without spec",
+			"no-spec",
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, metakubeResourceCluster().Schema, map[string]interface{}{
+				"dc_name": tc.DatacenterName,
+				"spec": []interface{}{
+					map[string]interface{}{
+						"cloud": []interface{}{
+							map[string]interface{}{
+								"openstack": []interface{}{map[string]interface{}{}},
+							},
+						},
+					},
+				},
+			})
+
+			ret := metakubeResourceClusterValidateDatacenterProvider(context.Background(), d, k)
+			if ret.HasError() != tc.ExpectedError {
+				t.Fatalf("Unexpected diagnostics: %v", ret)
+			}
+			if tc.ExpectedError && !cty.GetAttrPath("dc_name").Equals(ret[0].AttributePath) {
+				t.Fatalf("Unexpected attribute path: %#v", ret[0].AttributePath)
+			}
+		})
+	}
+}
+
+func TestMetakubeResourceClusterValidateSSHKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/projects/project-id/sshkeys":
+			w.Write([]byte(`[{"id":"key-1","name":"foo"},{"id":"key-2","name":"bar"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, diags := newClient(server.URL, defaultAPITimeout, nil)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	auth, diags := newAuth("token", "", "")
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	k := &metakubeProviderMeta{client: client, auth: auth}
+
+	cases := []struct {
+		Name            string
+		SSHKeys         []interface{}
+		ExpectedSummary []string
+	}{
+		{
+			"no sshkeys",
+			nil,
+			nil,
+		},
+		{
+			"existing sshkeys",
+			[]interface{}{"key-1", "key-2"},
+			nil,
+		},
+		{
+			"unknown sshkey",
+			[]interface{}{"key-1", "key-3"},
+			[]string{"SSH key 'key-3' not found in project 'project-id'"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, metakubeResourceCluster().Schema, map[string]interface{}{
+				"project_id": "project-id",
+				"sshkeys":    tc.SSHKeys,
+			})
+
+			var summary []string
+			for _, v := range metakubeResourceClusterValidateSSHKeys(context.Background(), d, k) {
+				summary = append(summary, v.Summary)
+			}
+			if diff := cmp.Diff(tc.ExpectedSummary, summary); diff != "" {
+				t.Fatalf("Unexpected diagnostics: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMetakubeResourceClusterValidateMachineNetworks(t *testing.T) {
+	cases := []struct {
+		Name                  string
+		Cloud                 string
+		MachineNetworks       []interface{}
+		ExpectedAttributePath cty.Path
+	}{
+		{
+			"no machine networks",
+			"openstack",
+			nil,
+			nil,
+		},
+		{
+			"gateway in network",
+			"vsphere",
+			[]interface{}{
+				map[string]interface{}{
+					"cidr":        "192.168.0.0/24",
+					"gateway":     "192.168.0.1",
+					"dns_servers": []interface{}{"8.8.8.8"},
+				},
+			},
+			nil,
+		},
+		{
+			"gateway outside of network",
+			"vsphere",
+			[]interface{}{
+				map[string]interface{}{
+					"cidr":    "192.168.0.0/24",
+					"gateway": "192.168.1.1",
+				},
+			},
+			cty.GetAttrPath("spec").IndexInt(0).GetAttr("machine_networks").IndexInt(0).GetAttr("gateway"),
+		},
+		{
+			"unsupported provider",
+			"openstack",
+			[]interface{}{
+				map[string]interface{}{
+					"cidr":    "192.168.0.0/24",
+					"gateway": "192.168.0.1",
+				},
+			},
+			cty.GetAttrPath("spec").IndexInt(0).GetAttr("machine_networks"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, metakubeResourceCluster().Schema, map[string]interface{}{
+				"spec": []interface{}{
+					map[string]interface{}{
+						"machine_networks": tc.MachineNetworks,
+						"cloud": []interface{}{
+							map[string]interface{}{
+								tc.Cloud: []interface{}{map[string]interface{}{}},
+							},
+						},
+					},
+				},
+			})
+
+			ret := metakubeResourceClusterValidateMachineNetworks(d)
+			if tc.ExpectedAttributePath == nil {
+				if len(ret) != 0 {
+					t.Fatalf("Unexpected diagnostics: %v", ret)
+				}
+				return
+			}
+			if len(ret) != 1 || !tc.ExpectedAttributePath.Equals(ret[0].AttributePath) {
+				t.Fatalf("Unexpected diagnostics: %#v", ret)
+			}
+		})
+	}
+}
+
+func TestMetakubeResourceClusterValidateAccessCredentialsSet(t *testing.T) {
+	cases := []struct {
+		Name             string
+		Openstack        map[string]interface{}
+		SuppressWarning  bool
+		ExpectedSeverity []diag.Severity
+	}{
+		{
+			"application credentials",
+			map[string]interface{}{
+				"application_credentials_id":     "id",
+				"application_credentials_secret": "secret",
+			},
+			false,
+			nil,
+		},
+		{
+			"username and password",
+			map[string]interface{}{
+				"username": "user",
+				"password": "pass",
+				"tenant":   "tenant",
+			},
+			false,
+			[]diag.Severity{diag.Warning},
+		},
+		{
+			"username and password with suppressed warning",
+			map[string]interface{}{
+				"username": "user",
+				"password": "pass",
+				"tenant":   "tenant",
+			},
+			true,
+			nil,
+		},
+		{
+			"username without password",
+			map[string]interface{}{
+				"username": "user",
+				"tenant":   "tenant",
+			},
+			false,
+			[]diag.Severity{diag.Error},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, metakubeResourceCluster().Schema, map[string]interface{}{
+				"spec": []interface{}{
+					map[string]interface{}{
+						"cloud": []interface{}{
+							map[string]interface{}{
+								"openstack": []interface{}{tc.Openstack},
+							},
+						},
+					},
+				},
+			})
+			k := &metakubeProviderMeta{suppressPasswordAuthWarning: tc.SuppressWarning}
+
+			var severity []diag.Severity
+			for _, v := range metakubeResourceClusterValidateAccessCredentialsSet(d, k) {
+				severity = append(severity, v.Severity)
+			}
+			if diff := cmp.Diff(tc.ExpectedSeverity, severity); diff != "" {
+				t.Fatalf("Unexpected diagnostics: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateLabelMap(t *testing.T) {
+	cases := []struct {
+		Name        string
+		Input       map[string]interface{}
+		ExpectError bool
+	}{
+		{
+			"valid",
+			map[string]interface{}{
+				"role":                          "worker",
+				"node.kubernetes.io/node-group": "group_1.a",
+				"empty":                         "",
+			},
+			false,
+		},
+		{
+			"invalid key",
+			map[string]interface{}{
+				"-role": "worker",
+			},
+			true,
+		},
+		{
+			"invalid key prefix",
+			map[string]interface{}{
+				"Example.com/role": "worker",
+			},
+			true,
+		},
+		{
+			"empty key name",
+			map[string]interface{}{
+				"example.com/": "worker",
+			},
+			true,
+		},
+		{
+			"invalid value",
+			map[string]interface{}{
+				"role": "worker=1",
+			},
+			true,
+		},
+		{
+			"value too long",
+			map[string]interface{}{
+				"role": strings.Repeat("a", 64),
+			},
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			diags := validateLabelMap(tc.Input, cty.GetAttrPath("default_node_selector"))
+			if diags.HasError() != tc.ExpectError {
+				t.Fatalf("want error %v, got %v", tc.ExpectError, diags)
+			}
+		})
+	}
+}
+
+func TestMetakubeGetMasterVersionsCached(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		calls++
+		w.Write([]byte(`[{"version":"1.21.5"},{"version":"1.20.11","default":true}]`))
+	}))
+	defer server.Close()
+
+	client, diags := newClient(server.URL, defaultAPITimeout, nil)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	k := &metakubeProviderMeta{client: client, masterVersions: &metakubeMasterVersionsCache{}}
+
+	for i := 0; i < 2; i++ {
+		if _, available, err := metakubeResourceClusterResolveVersion(context.Background(), k, "1.21"); err != nil || len(available) != 2 {
+			t.Fatalf("Unexpected result: %v %v", available, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("Expected versions to be listed once, got %d calls", calls)
+	}
+}
+
+func TestMetakubeResourceClusterMatchVersion(t *testing.T) {
+	available := []string{"1.27.3", "1.28.9", "1.28.10", "1.28.2", "1.29.0"}
+	cases := []struct {
+		Name     string
+		Version  string
+		Expected string
+	}{
+		{
+			"exact match",
+			"1.28.9",
+			"1.28.9",
+		},
+		{
+			"newest patch by semver",
+			"1.28",
+			"1.28.10",
+		},
+		{
+			"partial version with trailing zero patch",
+			"1.29",
+			"1.29.0",
+		},
+		{
+			"partial version matches whole segments",
+			"1.2",
+			"",
+		},
+		{
+			"no match",
+			"1.30",
+			"",
+		},
+		{
+			"unknown patch version",
+			"1.28.11",
+			"",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.Expected, metakubeResourceClusterMatchVersion(available, tc.Version)); diff != "" {
+				t.Fatalf("Unexpected version: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMetakubeResourceClusterIsPartialVersion(t *testing.T) {
+	cases := []struct {
+		Version  string
+		Expected bool
+	}{
+		{"1", true},
+		{"1.28", true},
+		{"1.28.5", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Version, func(t *testing.T) {
+			if got := metakubeResourceClusterIsPartialVersion(tc.Version); got != tc.Expected {
+				t.Fatalf("expected %v, got %v", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestMetakubeResourceClusterVersionDiffSuppress(t *testing.T) {
+	suppress := metakubeResourceClusterSpecFields()["version"].DiffSuppressFunc
+	cases := []struct {
+		Name     string
+		Old      string
+		New      string
+		Expected bool
+	}{
+		{
+			"partial version of current",
+			"1.28.5",
+			"1.28",
+			true,
+		},
+		{
+			"other patch version",
+			"1.28.5",
+			"1.28.6",
+			false,
+		},
+		{
+			"other minor version",
+			"1.28.5",
+			"1.29",
+			false,
+		},
+		{
+			"partial version matches whole segments",
+			"1.28.5",
+			"1.2",
+			false,
+		},
+		{
+			"new cluster",
+			"",
+			"1.28",
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := suppress("spec.0.version", tc.Old, tc.New, nil); got != tc.Expected {
+				t.Fatalf("expected %v, got %v", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestMetakubeResourceClusterVersionDiff(t *testing.T) {
+	cluster := func(version string) map[string]interface{} {
+		return map[string]interface{}{
+			"project_id": "project-id",
+			"dc_name":    "dbl1",
+			"name":       "cluster",
+			"spec": []interface{}{
+				map[string]interface{}{
+					"version": version,
+					"cloud": []interface{}{
+						map[string]interface{}{
+							"openstack": []interface{}{map[string]interface{}{}},
+						},
+					},
+				},
+			},
+		}
+	}
+	cases := []struct {
+		Name            string
+		Version         string
+		ExpectedDiff    bool
+		ExpectedReplace bool
+	}{
+		{
+			"partial version of current",
+			"1.28",
+			false,
+			false,
+		},
+		{
+			"upgrade",
+			"1.29",
+			true,
+			false,
+		},
+		{
+			"downgrade",
+			"1.27",
+			true,
+			true,
+		},
+	}
+
+	r := metakubeResourceCluster()
+	old := schema.TestResourceDataRaw(t, r.Schema, cluster("1.28.5"))
+	old.SetId("cluster-id")
+	state := old.State()
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(cluster(tc.Version)), nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			changed := false
+			if diff != nil {
+				_, changed = diff.GetAttribute("spec.0.version")
+			}
+			if changed != tc.ExpectedDiff {
+				t.Fatalf("want version diff %v, got %v", tc.ExpectedDiff, changed)
+			}
+			if replace := diff != nil && diff.RequiresNew(); replace != tc.ExpectedReplace {
+				t.Fatalf("want replace %v, got %v", tc.ExpectedReplace, replace)
+			}
+		})
+	}
+}
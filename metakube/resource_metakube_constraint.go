@@ -0,0 +1,432 @@
+package metakube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/constrainttemplates"
+	"github.com/syseleven/go-metakube/client/project"
+	"github.com/syseleven/go-metakube/models"
+	"gopkg.in/yaml.v2"
+)
+
+func metakubeResourceConstraint() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceConstraintCreate,
+		ReadContext:   metakubeResourceConstraintRead,
+		UpdateContext: metakubeResourceConstraintUpdate,
+		DeleteContext: metakubeResourceConstraintDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importResourceWithProjectAndClusterID("constraint_name"),
+		},
+		CustomizeDiff: validateConstraintTemplateExists(),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project the cluster belongs to",
+			},
+
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Cluster to apply the constraint to, OPA integration must be enabled",
+			},
+
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Constraint name",
+			},
+
+			"constraint_template": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Kind of the constraint template, e.g. K8sRequiredLabels",
+			},
+
+			"parameters": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateConstraintParameters,
+				DiffSuppressFunc: metakubeConstraintParametersDiffSuppress,
+				Description:      "Constraint parameters as JSON or YAML object",
+			},
+
+			// Computed, MetaKube may default the match of constraints created without one.
+			// Removing the block keeps the current match.
+			"match": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Description: "Resources the constraint applies to",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"kinds": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Groups and kinds of objects the constraint applies to",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"api_groups": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "API groups of the resources, empty string for the core group",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+									"kinds": {
+										Type:        schema.TypeList,
+										Required:    true,
+										Description: "Kinds of the resources",
+										Elem: &schema.Schema{
+											Type:         schema.TypeString,
+											ValidateFunc: validation.NoZeroValues,
+										},
+									},
+								},
+							},
+						},
+						"namespaces": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Only apply to resources in these namespaces",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"excluded_namespaces": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Don't apply to resources in these namespaces",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"scope": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "*",
+							ValidateFunc: validation.StringInSlice([]string{"*", "Cluster", "Namespaced"}, false),
+							Description:  "Scope of the resources, one of *, Cluster or Namespaced",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateConstraintTemplateExists() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		k := meta.(*metakubeProviderMeta)
+		kind := d.Get("constraint_template").(string)
+		if k.skipLiveValidation || kind == "" || !d.HasChange("constraint_template") {
+			return nil
+		}
+
+		p := constrainttemplates.NewListConstraintTemplatesParams().WithContext(ctx)
+		r, err := k.client.Constrainttemplates.ListConstraintTemplates(p, k.auth)
+		if err != nil {
+			k.log.Debugf("skip constraint template validation, unable to list constraint templates: %s", stringifyResponseError(err))
+			return nil
+		}
+		var available []string
+		for _, v := range r.Payload {
+			if v == nil || v.Spec == nil || v.Spec.Crd == nil || v.Spec.Crd.Spec == nil || v.Spec.Crd.Spec.Names == nil {
+				continue
+			}
+			if v.Spec.Crd.Spec.Names.Kind == kind {
+				return nil
+			}
+			available = append(available, v.Spec.Crd.Spec.Names.Kind)
+		}
+		sort.Strings(available)
+		return fmt.Errorf("constraint template '%s' not found, available templates: %s", kind, strings.Join(available, ", "))
+	}
+}
+
+func validateConstraintParameters(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := metakubeConstraintParseParameters(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %v", k, err)}
+	}
+	return nil, nil
+}
+
+// metakubeConstraintParametersDiffSuppress compares parameters by content,
+// so choice of JSON or YAML, formatting and order of keys don't cause a diff.
+func metakubeConstraintParametersDiffSuppress(_, old, new string, _ *schema.ResourceData) bool {
+	o, err := metakubeConstraintParseParameters(old)
+	if err != nil {
+		return false
+	}
+	n, err := metakubeConstraintParseParameters(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(o, n)
+}
+
+// metakubeConstraintParseParameters parses a JSON or YAML object, JSON being a subset of YAML.
+func metakubeConstraintParseParameters(in string) (map[string]interface{}, error) {
+	if strings.TrimSpace(in) == "" {
+		return nil, nil
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(in), &v); err != nil {
+		return nil, fmt.Errorf("parameters must be valid JSON or YAML: %v", err)
+	}
+	ret, ok := metakubeConstraintNormalizeParameters(v).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("parameters must be an object")
+	}
+	if len(ret) == 0 {
+		// Same as no parameters, cleared parameters are sent as empty object.
+		return nil, nil
+	}
+	return ret, nil
+}
+
+// metakubeConstraintNormalizeParameters converts YAML maps to JSON compatible maps with string keys.
+func metakubeConstraintNormalizeParameters(in interface{}) interface{} {
+	switch v := in.(type) {
+	case map[interface{}]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			ret[fmt.Sprint(key)] = metakubeConstraintNormalizeParameters(value)
+		}
+		return ret
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			ret[key] = metakubeConstraintNormalizeParameters(value)
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			ret = append(ret, metakubeConstraintNormalizeParameters(value))
+		}
+		return ret
+	default:
+		return v
+	}
+}
+
+// metakubeConstraintParametersJSON returns parameters as compact JSON with sorted keys.
+func metakubeConstraintParametersJSON(in string) (string, error) {
+	v, err := metakubeConstraintParseParameters(in)
+	if err != nil || v == nil {
+		return "", err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func metakubeResourceConstraintExpand(d *schema.ResourceData) (*models.Constraint, error) {
+	ret := &models.Constraint{
+		Name: d.Get("name").(string),
+		Spec: &models.ConstraintSpec{
+			ConstraintType: d.Get("constraint_template").(string),
+			Match:          metakubeConstraintExpandMatch(d.Get("match").([]interface{})),
+		},
+	}
+	if v := d.Get("parameters").(string); v != "" {
+		// Normalize, format of the configuration is not relevant for the API.
+		parameters, err := metakubeConstraintParametersJSON(v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse parameters: %v", err)
+		}
+		ret.Spec.Parameters = &models.Parameters{RawJSON: parameters}
+	}
+	return ret, nil
+}
+
+func metakubeConstraintExpandMatch(p []interface{}) *models.Match {
+	if len(p) < 1 || p[0] == nil {
+		return nil
+	}
+	in := p[0].(map[string]interface{})
+	ret := &models.Match{}
+	if v, ok := in["kinds"].([]interface{}); ok {
+		for _, kind := range v {
+			m, ok := kind.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ret.Kinds = append(ret.Kinds, &models.Kind{
+				APIGroups: metakubeConstraintExpandStrings(m["api_groups"]),
+				Kinds:     metakubeConstraintExpandStrings(m["kinds"]),
+			})
+		}
+	}
+	ret.Namespaces = metakubeConstraintExpandStrings(in["namespaces"])
+	ret.ExcludedNamespaces = metakubeConstraintExpandStrings(in["excluded_namespaces"])
+	if v, ok := in["scope"].(string); ok {
+		ret.Scope = v
+	}
+	return ret
+}
+
+func metakubeConstraintExpandStrings(in interface{}) []string {
+	v, ok := in.([]interface{})
+	if !ok {
+		return nil
+	}
+	ret := make([]string, 0, len(v))
+	for _, s := range v {
+		// Empty string is the core API group.
+		str, _ := s.(string)
+		ret = append(ret, str)
+	}
+	return ret
+}
+
+func metakubeConstraintFlattenMatch(in *models.Match) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+	var kinds []interface{}
+	for _, v := range in.Kinds {
+		if v == nil {
+			continue
+		}
+		kinds = append(kinds, map[string]interface{}{
+			"api_groups": metakubeConstraintFlattenStrings(v.APIGroups),
+			"kinds":      metakubeConstraintFlattenStrings(v.Kinds),
+		})
+	}
+	scope := in.Scope
+	if scope == "" {
+		scope = "*"
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"kinds":               kinds,
+			"namespaces":          metakubeConstraintFlattenStrings(in.Namespaces),
+			"excluded_namespaces": metakubeConstraintFlattenStrings(in.ExcludedNamespaces),
+			"scope":               scope,
+		},
+	}
+}
+
+func metakubeConstraintFlattenStrings(in []string) []interface{} {
+	ret := make([]interface{}, 0, len(in))
+	for _, v := range in {
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+func metakubeResourceConstraintCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	constraint, err := metakubeResourceConstraintExpand(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	p := project.NewCreateConstraintParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string)).
+		WithBody(&models.ConstraintBody{
+			Name: constraint.Name,
+			Spec: constraint.Spec,
+		})
+	r, err := k.client.Project.CreateConstraint(p, k.auth)
+	if err != nil {
+		return diag.Errorf("unable to create constraint '%s': %s", constraint.Name, stringifyResponseError(err))
+	}
+	d.SetId(r.Payload.Name)
+
+	return metakubeResourceConstraintRead(ctx, d, m)
+}
+
+func metakubeResourceConstraintRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewGetConstraintParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string)).
+		WithName(d.Id())
+	r, err := k.client.Project.GetConstraint(p, k.auth)
+	if err != nil {
+		if isNotFound(err) {
+			k.log.Infof("removing constraint '%s' from terraform state file, could not find the resource", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to get constraint '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	_ = d.Set("name", r.Payload.Name)
+	if spec := r.Payload.Spec; spec != nil {
+		_ = d.Set("constraint_template", spec.ConstraintType)
+		parameters := ""
+		if spec.Parameters != nil && spec.Parameters.RawJSON != "" {
+			v, err := metakubeConstraintParametersJSON(spec.Parameters.RawJSON)
+			if err != nil {
+				return diag.Errorf("unable to read parameters of constraint '%s': %v", d.Id(), err)
+			}
+			parameters = v
+		}
+		_ = d.Set("parameters", parameters)
+		_ = d.Set("match", metakubeConstraintFlattenMatch(spec.Match))
+	}
+
+	return nil
+}
+
+func metakubeResourceConstraintUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	constraint, err := metakubeResourceConstraintExpand(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if constraint.Spec.Parameters == nil && d.HasChange("parameters") {
+		// Omitted parameters are not changed by the patch, clear them explicitly.
+		constraint.Spec.Parameters = &models.Parameters{RawJSON: "{}"}
+	}
+	p := project.NewPatchConstraintParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string)).
+		WithName(d.Id()).
+		WithPatch(constraint)
+	if _, err := k.client.Project.PatchConstraint(p, k.auth); err != nil {
+		return diag.Errorf("unable to update constraint '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	return metakubeResourceConstraintRead(ctx, d, m)
+}
+
+func metakubeResourceConstraintDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewDeleteConstraintParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string)).
+		WithName(d.Id())
+	if _, err := k.client.Project.DeleteConstraint(p, k.auth); err != nil && !isNotFound(err) {
+		return diag.Errorf("unable to delete constraint '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+	return nil
+}
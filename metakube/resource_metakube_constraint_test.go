@@ -0,0 +1,182 @@
+package metakube
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func TestMetakubeConstraintExpandMatch(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          []interface{}
+		ExpectedOutput *models.Match
+	}{
+		{
+			"no match",
+			[]interface{}{},
+			nil,
+		},
+		{
+			"core api group",
+			[]interface{}{
+				map[string]interface{}{
+					"kinds": []interface{}{
+						map[string]interface{}{
+							"api_groups": []interface{}{""},
+							"kinds":      []interface{}{"Namespace"},
+						},
+					},
+					"namespaces":          []interface{}{"default"},
+					"excluded_namespaces": []interface{}{"kube-system"},
+					"scope":               "*",
+				},
+			},
+			&models.Match{
+				Kinds: []*models.Kind{
+					{
+						APIGroups: []string{""},
+						Kinds:     []string{"Namespace"},
+					},
+				},
+				Namespaces:         []string{"default"},
+				ExcludedNamespaces: []string{"kube-system"},
+				Scope:              "*",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			output := metakubeConstraintExpandMatch(tc.Input)
+			if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+				t.Fatalf("Unexpected output from expander: mismatch (-want +got):\n%s", diff)
+			}
+			if output == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.Input, metakubeConstraintFlattenMatch(output)); diff != "" {
+				t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMetakubeConstraintParametersDiffSuppress(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Old      string
+		New      string
+		Expected bool
+	}{
+		{
+			"same json",
+			`{"labels":[{"key":"owner"}]}`,
+			`{"labels":[{"key":"owner"}]}`,
+			true,
+		},
+		{
+			"json formatting and key order",
+			`{"a":1,"labels":[{"key":"owner"}]}`,
+			"{\n  \"labels\": [ { \"key\": \"owner\" } ],\n  \"a\": 1\n}",
+			true,
+		},
+		{
+			"yaml equal to json",
+			`{"labels":[{"key":"owner"}]}`,
+			"labels:\n- key: owner\n",
+			true,
+		},
+		{
+			"different value",
+			`{"labels":[{"key":"owner"}]}`,
+			"labels:\n- key: team\n",
+			false,
+		},
+		{
+			"different list order",
+			`{"labels":[{"key":"owner"},{"key":"team"}]}`,
+			`{"labels":[{"key":"team"},{"key":"owner"}]}`,
+			false,
+		},
+		{
+			"removed",
+			`{"labels":[{"key":"owner"}]}`,
+			"",
+			false,
+		},
+		{
+			"invalid",
+			`{"labels":[{"key":"owner"}]}`,
+			`{"labels":`,
+			false,
+		},
+		{
+			"cleared",
+			"{}",
+			"",
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := metakubeConstraintParametersDiffSuppress("parameters", tc.Old, tc.New, nil); got != tc.Expected {
+				t.Fatalf("expected %v, got %v", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestMetakubeConstraintParametersJSON(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Input         string
+		Expected      string
+		ExpectedError bool
+	}{
+		{
+			"empty",
+			"",
+			"",
+			false,
+		},
+		{
+			"json",
+			`{ "labels": [ { "key": "owner" } ], "a": 1 }`,
+			`{"a":1,"labels":[{"key":"owner"}]}`,
+			false,
+		},
+		{
+			"yaml",
+			"labels:\n- key: owner\na: 1\n",
+			`{"a":1,"labels":[{"key":"owner"}]}`,
+			false,
+		},
+		{
+			"empty object",
+			"{}",
+			"",
+			false,
+		},
+		{
+			"not an object",
+			"- owner\n",
+			"",
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := metakubeConstraintParametersJSON(tc.Input)
+			if (err != nil) != tc.ExpectedError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.Expected, got); diff != "" {
+				t.Fatalf("Unexpected output: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
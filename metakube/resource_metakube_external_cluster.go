@@ -0,0 +1,167 @@
+package metakube
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/project"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func metakubeResourceExternalCluster() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceExternalClusterCreate,
+		ReadContext:   metakubeResourceExternalClusterRead,
+		UpdateContext: metakubeResourceExternalClusterUpdate,
+		DeleteContext: metakubeResourceExternalClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: metakubeResourceExternalClusterImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project to register the cluster in",
+			},
+
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Name of the cluster in MetaKube",
+			},
+
+			"kubeconfig": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Sensitive:    true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Kubeconfig MetaKube uses to access the cluster, it is not returned by MetaKube API",
+			},
+
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Kubernetes version of the cluster",
+			},
+
+			"node_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of nodes in the cluster",
+			},
+		},
+	}
+}
+
+func metakubeResourceExternalClusterImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("please provide resource identifier in format 'project_id:cluster_id'")
+	}
+	d.Set("project_id", parts[0])
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+func metakubeResourceExternalClusterBody(d *schema.ResourceData) *models.Body {
+	return &models.Body{
+		Name:       d.Get("name").(string),
+		Kubeconfig: base64.StdEncoding.EncodeToString([]byte(d.Get("kubeconfig").(string))),
+	}
+}
+
+func metakubeResourceExternalClusterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewCreateExternalClusterParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithBody(metakubeResourceExternalClusterBody(d))
+	r, err := k.client.Project.CreateExternalCluster(p, k.auth)
+	if err != nil {
+		return diag.Errorf("unable to register external cluster '%s': %s", d.Get("name").(string), stringifyResponseError(err))
+	}
+	d.SetId(r.Payload.ID)
+
+	return metakubeResourceExternalClusterRead(ctx, d, m)
+}
+
+func metakubeResourceExternalClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	p := project.NewGetExternalClusterParams().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithClusterID(d.Id())
+	r, err := k.client.Project.GetExternalCluster(p, k.auth)
+	if err != nil {
+		if isNotFound(err) {
+			k.log.Infof("removing external cluster '%s' from terraform state file, could not find the resource", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to get external cluster '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	_ = d.Set("name", r.Payload.Name)
+	version := ""
+	if r.Payload.Status != nil {
+		version, _ = r.Payload.Status.Version.(string)
+	}
+	if version == "" && r.Payload.Spec != nil {
+		version, _ = r.Payload.Spec.Version.(string)
+	}
+	_ = d.Set("version", version)
+
+	np := project.NewListExternalClusterNodesParams().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithClusterID(d.Id())
+	nodes, err := k.client.Project.ListExternalClusterNodes(np, k.auth)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Warning,
+			Summary:       fmt.Sprintf("could not list nodes of external cluster '%s'", d.Id()),
+			Detail:        stringifyResponseError(err),
+			AttributePath: cty.GetAttrPath("node_count"),
+		}}
+	}
+	_ = d.Set("node_count", len(nodes.Payload))
+
+	return nil
+}
+
+func metakubeResourceExternalClusterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewUpdateExternalClusterParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Id()).
+		WithBody(metakubeResourceExternalClusterBody(d))
+	if _, err := k.client.Project.UpdateExternalCluster(p, k.auth); err != nil {
+		return diag.Errorf("unable to update external cluster '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	return metakubeResourceExternalClusterRead(ctx, d, m)
+}
+
+func metakubeResourceExternalClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewDeleteExternalClusterParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Id())
+	if _, err := k.client.Project.DeleteExternalCluster(p, k.auth); err != nil && !isNotFound(err) {
+		return diag.Errorf("unable to deregister external cluster '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+	return nil
+}
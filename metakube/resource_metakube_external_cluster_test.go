@@ -0,0 +1,125 @@
+package metakube
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/syseleven/go-metakube/client/project"
+)
+
+func TestAccMetakubeExternalCluster_Basic(t *testing.T) {
+	resourceName := "metakube_external_cluster.acctest"
+	params := &testAccCheckMetaKubeExternalClusterBasicParams{
+		ClusterName:                          makeRandomName(),
+		DatacenterName:                       os.Getenv(testEnvOpenstackNodeDC),
+		ProjectID:                            os.Getenv(testEnvProjectID),
+		Version:                              os.Getenv(testEnvK8sVersion),
+		OpenstackApplicationCredentialID:     os.Getenv(testEnvOpenstackApplicationCredentialsID),
+		OpenstackApplicationCredentialSecret: os.Getenv(testEnvOpenstackApplicationCredentialsSecret),
+
+		ExternalClusterName: makeRandomName(),
+	}
+	updatedParams := *params
+	updatedParams.ExternalClusterName = makeRandomName()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetaKubeExternalClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckMetaKubeExternalClusterBasicConfig(t, params),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", params.ExternalClusterName),
+					resource.TestCheckResourceAttrSet(resourceName, "version"),
+					resource.TestCheckResourceAttrSet(resourceName, "node_count"),
+				),
+			},
+			{
+				Config: testAccCheckMetaKubeExternalClusterBasicConfig(t, &updatedParams),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", updatedParams.ExternalClusterName),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"kubeconfig"},
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("not found")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project_id"], rs.Primary.ID), nil
+				},
+			},
+		},
+	})
+}
+
+type testAccCheckMetaKubeExternalClusterBasicParams struct {
+	ClusterName                          string
+	DatacenterName                       string
+	ProjectID                            string
+	Version                              string
+	OpenstackApplicationCredentialID     string
+	OpenstackApplicationCredentialSecret string
+
+	ExternalClusterName string
+}
+
+func testAccCheckMetaKubeExternalClusterBasicConfig(t *testing.T, params *testAccCheckMetaKubeExternalClusterBasicParams) string {
+	t.Helper()
+
+	var result strings.Builder
+	err := mustParseTemplate("external cluster test template", `
+resource "metakube_cluster" "acctest" {
+	name = "{{ .ClusterName }}"
+	dc_name = "{{ .DatacenterName }}"
+	project_id = "{{ .ProjectID }}"
+
+	spec {
+		version = "{{ .Version }}"
+		cloud {
+			openstack {
+				application_credentials_id="{{ .OpenstackApplicationCredentialID }}"
+				application_credentials_secret="{{ .OpenstackApplicationCredentialSecret }}"
+			}
+		}
+	}
+}
+
+resource "metakube_external_cluster" "acctest" {
+	project_id = "{{ .ProjectID }}"
+	name = "{{ .ExternalClusterName }}"
+	kubeconfig = metakube_cluster.acctest.kube_config
+}
+`).Execute(&result, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.String()
+}
+
+func testAccCheckMetaKubeExternalClusterDestroy(s *terraform.State) error {
+	k := testAccProvider.Meta().(*metakubeProviderMeta)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metakube_external_cluster" {
+			continue
+		}
+
+		p := project.NewGetExternalClusterParams().
+			WithProjectID(rs.Primary.Attributes["project_id"]).
+			WithClusterID(rs.Primary.ID)
+		if _, err := k.client.Project.GetExternalCluster(p, k.auth); err == nil {
+			return fmt.Errorf("External cluster still exists")
+		}
+	}
+
+	return testAccCheckMetaKubeClusterDestroy(s)
+}
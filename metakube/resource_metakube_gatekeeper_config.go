@@ -0,0 +1,266 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/project"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func metakubeResourceGatekeeperConfig() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceGatekeeperConfigCreate,
+		ReadContext:   metakubeResourceGatekeeperConfigRead,
+		UpdateContext: metakubeResourceGatekeeperConfigUpdate,
+		DeleteContext: metakubeResourceGatekeeperConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: metakubeResourceGatekeeperConfigImport,
+		},
+		CustomizeDiff: validateGatekeeperConfigClusterOPAEnabled(),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project the cluster belongs to",
+			},
+
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Cluster to configure Gatekeeper of, OPA integration must be enabled",
+			},
+
+			"match": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Namespaces excluded from Gatekeeper processes",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"excluded_namespaces": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							Description: "Namespaces to exclude",
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.NoZeroValues,
+							},
+						},
+						"processes": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							Description: "Processes the namespaces are excluded from, one of sync, webhook, audit or *",
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{"sync", "webhook", "audit", "*"}, false),
+							},
+						},
+					},
+				},
+			},
+
+			"sync_only": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Resources replicated into OPA, e.g. to reference them in constraint templates",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "API group, empty for the core group",
+						},
+						"version": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.NoZeroValues,
+							Description:  "API version",
+						},
+						"kind": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.NoZeroValues,
+							Description:  "Resource kind",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func metakubeResourceGatekeeperConfigImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("please provide resource identifier in format 'project_id:cluster_id'")
+	}
+	d.Set("project_id", parts[0])
+	d.Set("cluster_id", parts[1])
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+func validateGatekeeperConfigClusterOPAEnabled() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		k := meta.(*metakubeProviderMeta)
+		projectID := d.Get("project_id").(string)
+		clusterID := d.Get("cluster_id").(string)
+		if k.skipLiveValidation || projectID == "" || clusterID == "" || !d.HasChange("cluster_id") {
+			return nil
+		}
+
+		cluster, ok, err := metakubeGetCluster(ctx, projectID, clusterID, k)
+		if err != nil || !ok {
+			k.log.Debugf("skip gatekeeper config validation, unable to get cluster '%s': %v", clusterID, err)
+			return nil
+		}
+		if cluster.Spec == nil || cluster.Spec.OpaIntegration == nil || !cluster.Spec.OpaIntegration.Enabled {
+			return fmt.Errorf("OPA integration is not enabled for cluster '%s', enable it to configure Gatekeeper", clusterID)
+		}
+		return nil
+	}
+}
+
+func metakubeGatekeeperConfigExpand(d *schema.ResourceData) *models.GatekeeperConfig {
+	spec := &models.GatekeeperConfigSpec{
+		Match: []*models.MatchEntry{},
+		Sync: &models.Sync{
+			SyncOnly: []*models.GVK{},
+		},
+	}
+	for _, v := range d.Get("match").(*schema.Set).List() {
+		m := v.(map[string]interface{})
+		spec.Match = append(spec.Match, &models.MatchEntry{
+			ExcludedNamespaces: metakubeGatekeeperConfigExpandStringSet(m["excluded_namespaces"]),
+			Processes:          metakubeGatekeeperConfigExpandStringSet(m["processes"]),
+		})
+	}
+	for _, v := range d.Get("sync_only").(*schema.Set).List() {
+		m := v.(map[string]interface{})
+		spec.Sync.SyncOnly = append(spec.Sync.SyncOnly, &models.GVK{
+			Group:   m["group"].(string),
+			Version: m["version"].(string),
+			Kind:    m["kind"].(string),
+		})
+	}
+	return &models.GatekeeperConfig{Spec: spec}
+}
+
+func metakubeGatekeeperConfigExpandStringSet(in interface{}) []string {
+	s, ok := in.(*schema.Set)
+	if !ok {
+		return nil
+	}
+	ret := make([]string, 0, s.Len())
+	for _, v := range s.List() {
+		ret = append(ret, v.(string))
+	}
+	return ret
+}
+
+func metakubeGatekeeperConfigFlattenMatch(in []*models.MatchEntry) []interface{} {
+	ret := make([]interface{}, 0, len(in))
+	for _, v := range in {
+		if v == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"excluded_namespaces": metakubeConstraintFlattenStrings(v.ExcludedNamespaces),
+			"processes":           metakubeConstraintFlattenStrings(v.Processes),
+		})
+	}
+	return ret
+}
+
+func metakubeGatekeeperConfigFlattenSyncOnly(in *models.Sync) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+	ret := make([]interface{}, 0, len(in.SyncOnly))
+	for _, v := range in.SyncOnly {
+		if v == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"group":   v.Group,
+			"version": v.Version,
+			"kind":    v.Kind,
+		})
+	}
+	return ret
+}
+
+func metakubeResourceGatekeeperConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	clusterID := d.Get("cluster_id").(string)
+	p := project.NewCreateGatekeeperConfigParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(clusterID).
+		WithBody(metakubeGatekeeperConfigExpand(d))
+	if _, err := k.client.Project.CreateGatekeeperConfig(p, k.auth); err != nil {
+		return diag.Errorf("unable to create gatekeeper config of cluster '%s': %s", clusterID, stringifyResponseError(err))
+	}
+	d.SetId(clusterID)
+
+	return metakubeResourceGatekeeperConfigRead(ctx, d, m)
+}
+
+func metakubeResourceGatekeeperConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewGetGatekeeperConfigParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string))
+	r, err := k.client.Project.GetGatekeeperConfig(p, k.auth)
+	if err != nil {
+		if isNotFound(err) {
+			k.log.Infof("removing gatekeeper config '%s' from terraform state file, could not find the resource", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to get gatekeeper config '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	if spec := r.Payload.Spec; spec != nil {
+		_ = d.Set("match", metakubeGatekeeperConfigFlattenMatch(spec.Match))
+		_ = d.Set("sync_only", metakubeGatekeeperConfigFlattenSyncOnly(spec.Sync))
+	}
+
+	return nil
+}
+
+func metakubeResourceGatekeeperConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewPatchGatekeeperConfigParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string)).
+		WithPatch(metakubeGatekeeperConfigExpand(d))
+	if _, err := k.client.Project.PatchGatekeeperConfig(p, k.auth); err != nil {
+		return diag.Errorf("unable to update gatekeeper config '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	return metakubeResourceGatekeeperConfigRead(ctx, d, m)
+}
+
+func metakubeResourceGatekeeperConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewDeleteGatekeeperConfigParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string))
+	if _, err := k.client.Project.DeleteGatekeeperConfig(p, k.auth); err != nil && !isNotFound(err) {
+		return diag.Errorf("unable to delete gatekeeper config '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package metakube
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func TestMetakubeGatekeeperConfigExpand(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Input          map[string]interface{}
+		ExpectedOutput *models.GatekeeperConfig
+	}{
+		{
+			"empty",
+			map[string]interface{}{},
+			&models.GatekeeperConfig{
+				Spec: &models.GatekeeperConfigSpec{
+					Match: []*models.MatchEntry{},
+					Sync: &models.Sync{
+						SyncOnly: []*models.GVK{},
+					},
+				},
+			},
+		},
+		{
+			"match and sync",
+			map[string]interface{}{
+				"match": []interface{}{
+					map[string]interface{}{
+						"excluded_namespaces": []interface{}{"kube-system"},
+						"processes":           []interface{}{"*"},
+					},
+				},
+				"sync_only": []interface{}{
+					map[string]interface{}{
+						"group":   "",
+						"version": "v1",
+						"kind":    "Namespace",
+					},
+				},
+			},
+			&models.GatekeeperConfig{
+				Spec: &models.GatekeeperConfigSpec{
+					Match: []*models.MatchEntry{
+						{
+							ExcludedNamespaces: []string{"kube-system"},
+							Processes:          []string{"*"},
+						},
+					},
+					Sync: &models.Sync{
+						SyncOnly: []*models.GVK{
+							{
+								Version: "v1",
+								Kind:    "Namespace",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, metakubeResourceGatekeeperConfig().Schema, tc.Input)
+			output := metakubeGatekeeperConfigExpand(d)
+			if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+				t.Fatalf("Unexpected output from expander: mismatch (-want +got):\n%s", diff)
+			}
+			for _, f := range []struct {
+				key    string
+				output []interface{}
+			}{
+				{"match", metakubeGatekeeperConfigFlattenMatch(output.Spec.Match)},
+				{"sync_only", metakubeGatekeeperConfigFlattenSyncOnly(output.Spec.Sync)},
+			} {
+				want, ok := tc.Input[f.key]
+				if !ok {
+					want = []interface{}{}
+				}
+				if diff := cmp.Diff(want, f.output); diff != "" {
+					t.Fatalf("Unexpected output from %s flattener: mismatch (-want +got):\n%s", f.key, diff)
+				}
+			}
+		})
+	}
+}
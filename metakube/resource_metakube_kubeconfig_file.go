@@ -0,0 +1,206 @@
+package metakube
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func metakubeResourceKubeconfigFile() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceKubeconfigFileCreate,
+		ReadContext:   metakubeResourceKubeconfigFileRead,
+		UpdateContext: metakubeResourceKubeconfigFileUpdate,
+		DeleteContext: metakubeResourceKubeconfigFileDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project the cluster belongs to",
+			},
+
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Cluster to write the admin kubeconfig of",
+			},
+
+			"path": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Path of the kubeconfig file",
+			},
+
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "0600",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^0?[0-7]{3}$`), "must be an octal file mode, e.g. 0600"),
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					o, _ := strconv.ParseUint(old, 8, 32)
+					n, _ := strconv.ParseUint(new, 8, 32)
+					return o == n
+				},
+				Description: "Permissions of the kubeconfig file",
+			},
+
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "API server endpoint of the cluster",
+			},
+
+			"cluster_ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PEM encoded CA certificate of the cluster",
+			},
+
+			"content_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA256 checksum of the kubeconfig file",
+			},
+		},
+	}
+}
+
+func metakubeResourceKubeconfigFileCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	path := d.Get("path").(string)
+
+	raw, err := metakubeClusterUpdateKubeconfig(ctx, k, d.Get("project_id").(string), d.Get("cluster_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	mode, _ := strconv.ParseUint(d.Get("mode").(string), 8, 32)
+	if err := metakubeWriteFileAtomic(path, []byte(raw), os.FileMode(mode)); err != nil {
+		return diag.Errorf("unable to write kubeconfig file '%s': %v", path, err)
+	}
+	d.SetId(path)
+
+	return metakubeResourceKubeconfigFileRead(ctx, d, m)
+}
+
+func metakubeResourceKubeconfigFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+	path := d.Get("path").(string)
+
+	_, ok, err := metakubeGetCluster(ctx, projectID, clusterID, k)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !ok {
+		k.log.Infof("removing kubeconfig file '%s' from terraform state file, could not find the cluster", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	raw, err := metakubeClusterUpdateKubeconfig(ctx, k, projectID, clusterID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	host, ca, _, err := metakubeParseKubeconfig(raw)
+	if err != nil {
+		return diag.Errorf("unable to parse kubeconfig of cluster '%s': %v", clusterID, err)
+	}
+
+	// Missing or outdated files, e.g. after the cluster endpoint or CA changed, are recreated on next apply.
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			k.log.Infof("removing kubeconfig file '%s' from terraform state file, could not find the file", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to read kubeconfig file '%s': %v", path, err)
+	}
+	checksum := metakubeSHA256(content)
+	if checksum != metakubeSHA256([]byte(raw)) {
+		k.log.Infof("removing kubeconfig file '%s' from terraform state file, content differs from cluster kubeconfig", d.Id())
+		d.SetId("")
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return diag.Errorf("unable to stat kubeconfig file '%s': %v", path, err)
+	}
+
+	_ = d.Set("mode", fmt.Sprintf("%04o", info.Mode().Perm()))
+	_ = d.Set("host", host)
+	_ = d.Set("cluster_ca_certificate", ca)
+	_ = d.Set("content_sha256", checksum)
+
+	return nil
+}
+
+func metakubeResourceKubeconfigFileUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	path := d.Get("path").(string)
+	if d.HasChange("mode") {
+		mode, _ := strconv.ParseUint(d.Get("mode").(string), 8, 32)
+		if err := os.Chmod(path, os.FileMode(mode)); err != nil {
+			return diag.Errorf("unable to change mode of kubeconfig file '%s': %v", path, err)
+		}
+	}
+
+	return metakubeResourceKubeconfigFileRead(ctx, d, m)
+}
+
+func metakubeResourceKubeconfigFileDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	path := d.Get("path").(string)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return diag.Errorf("unable to remove kubeconfig file '%s': %v", path, err)
+	}
+	return nil
+}
+
+// metakubeWriteFileAtomic writes to a temporary file in the target directory and renames it,
+// so readers never see a partially written kubeconfig.
+func metakubeWriteFileAtomic(path string, content []byte, mode os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func metakubeSHA256(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,144 @@
+package metakube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestMetakubeWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "kubeconfig")
+
+	for _, content := range []string{"first", "second"} {
+		if err := metakubeWriteFileAtomic(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(content, string(got)); diff != "" {
+			t.Fatalf("Unexpected file content: mismatch (-want +got):\n%s", diff)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(os.FileMode(0600), info.Mode().Perm()); diff != "" {
+			t.Fatalf("Unexpected file mode: mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected only the kubeconfig file, got %d files", len(files))
+	}
+}
+
+func TestAccMetakubeKubeconfigFile_Basic(t *testing.T) {
+	resourceName := "metakube_kubeconfig_file.acctest"
+	params := &testAccCheckMetaKubeKubeconfigFileBasicParams{
+		ClusterName:                          makeRandomName(),
+		DatacenterName:                       os.Getenv(testEnvOpenstackNodeDC),
+		ProjectID:                            os.Getenv(testEnvProjectID),
+		Version:                              os.Getenv(testEnvK8sVersion),
+		OpenstackApplicationCredentialID:     os.Getenv(testEnvOpenstackApplicationCredentialsID),
+		OpenstackApplicationCredentialSecret: os.Getenv(testEnvOpenstackApplicationCredentialsSecret),
+
+		Path: filepath.Join(t.TempDir(), "kubeconfig"),
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckMetaKubeClusterDestroy,
+			testAccCheckMetaKubeKubeconfigFileRemoved(params.Path),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckMetaKubeKubeconfigFileBasicConfig(t, params),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "mode", "0600"),
+					resource.TestCheckResourceAttrSet(resourceName, "host"),
+					resource.TestCheckResourceAttrSet(resourceName, "cluster_ca_certificate"),
+					resource.TestCheckResourceAttrSet(resourceName, "content_sha256"),
+				),
+			},
+			{
+				PreConfig: func() {
+					if err := os.Remove(params.Path); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccCheckMetaKubeKubeconfigFileBasicConfig(t, params),
+				Check: func(s *terraform.State) error {
+					_, err := os.Stat(params.Path)
+					return err
+				},
+			},
+		},
+	})
+}
+
+type testAccCheckMetaKubeKubeconfigFileBasicParams struct {
+	ClusterName                          string
+	DatacenterName                       string
+	ProjectID                            string
+	Version                              string
+	OpenstackApplicationCredentialID     string
+	OpenstackApplicationCredentialSecret string
+
+	Path string
+}
+
+func testAccCheckMetaKubeKubeconfigFileBasicConfig(t *testing.T, params *testAccCheckMetaKubeKubeconfigFileBasicParams) string {
+	t.Helper()
+
+	var result strings.Builder
+	err := mustParseTemplate("kubeconfig file test template", `
+resource "metakube_cluster" "acctest" {
+	name = "{{ .ClusterName }}"
+	dc_name = "{{ .DatacenterName }}"
+	project_id = "{{ .ProjectID }}"
+
+	spec {
+		version = "{{ .Version }}"
+		cloud {
+			openstack {
+				application_credentials_id="{{ .OpenstackApplicationCredentialID }}"
+				application_credentials_secret="{{ .OpenstackApplicationCredentialSecret }}"
+			}
+		}
+	}
+}
+
+resource "metakube_kubeconfig_file" "acctest" {
+	project_id = "{{ .ProjectID }}"
+	cluster_id = metakube_cluster.acctest.id
+	path = "{{ .Path }}"
+}
+`).Execute(&result, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.String()
+}
+
+func testAccCheckMetaKubeKubeconfigFileRemoved(path string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			return fmt.Errorf("kubeconfig file %s was not removed", path)
+		}
+		return nil
+	}
+}
@@ -2,16 +2,20 @@ package metakube
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/syseleven/go-metakube/client/project"
 	"github.com/syseleven/go-metakube/client/versions"
 	"github.com/syseleven/go-metakube/models"
@@ -24,11 +28,19 @@ func metakubeResourceNodeDeployment() *schema.Resource {
 		UpdateContext: metakubeResourceNodeDeploymentUpdate,
 		DeleteContext: metakubeResourceNodeDeploymentDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: importResourceWithProjectAndClusterID("node_deployment_name"),
+			StateContext: metakubeResourceNodeDeploymentImport,
 		},
 		CustomizeDiff: customdiff.All(
 			validateNodeSpecMatchesCluster(),
 			validateAutoscalerFields(),
+			validatePropagateLabels(),
+			validateNodeDeploymentNameUnique(),
+			validateDigitaloceanSize(),
+			validateAzureSize(),
+			validateAWSInstanceTypeAndSubnet(),
+			customdiff.ComputedIf("resolved_version", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+				return d.HasChange("spec.0.template.0.versions.0.kubelet")
+			}),
 		),
 
 		Timeouts: &schema.ResourceTimeout{
@@ -53,11 +65,24 @@ func metakubeResourceNodeDeployment() *schema.Resource {
 			},
 
 			"name": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				ForceNew:    true,
-				Description: "Node deployment name",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"name_prefix"},
+				Description:   "Node deployment name",
+			},
+
+			"name_prefix": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"name"},
+				ValidateFunc: validation.All(
+					validation.StringLenBetween(1, 50),
+					validation.StringMatch(regexp.MustCompile(`^[a-z0-9][-a-z0-9]*$`), "must consist of lower case alphanumeric characters or '-' and start with an alphanumeric character"),
+				),
+				Description: "Creates a unique name beginning with the prefix",
 			},
 
 			"spec": {
@@ -70,6 +95,35 @@ func metakubeResourceNodeDeployment() *schema.Resource {
 				},
 			},
 
+			"force_recreate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Changing this value deletes the node deployment with all its machines and creates it again",
+			},
+
+			"wait_for_rollout": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Wait until all replicas of node deployment are ready on create and update",
+			},
+
+			"status": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rollout status of node deployment",
+				Elem: &schema.Resource{
+					Schema: metakubeResourceNodeDeploymentStatusFields(),
+				},
+			},
+
+			"resolved_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Kubelet version the nodes run, also when the version is inherited from the cluster",
+			},
+
 			"creation_timestamp": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -85,6 +139,15 @@ func metakubeResourceNodeDeployment() *schema.Resource {
 	}
 }
 
+func metakubeResourceNodeDeploymentImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	ret, err := importResourceWithProjectAndClusterID("node_deployment_name")(ctx, d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.Set("wait_for_rollout", true)
+	return ret, nil
+}
+
 func importResourceWithProjectAndClusterID(identifierName string) func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 		parts := strings.Split(d.Id(), ":")
@@ -114,13 +177,19 @@ func metakubeResourceNodeDeploymentCreate(ctx context.Context, d *schema.Resourc
 		}
 	}
 
+	name := d.Get("name").(string)
+	if prefix, ok := d.GetOk("name_prefix"); ok {
+		name = metakubeNodeDeploymentGenerateName(prefix.(string))
+	}
+
 	nodeDeployment := &models.NodeDeployment{
-		Name: d.Get("name").(string),
+		Name: name,
 		Spec: metakubeNodeDeploymentExpandSpec(d.Get("spec").([]interface{})),
 	}
 
-	if err := metakubeResourceNodeDeploymentVersionCompatibleWithCluster(ctx, k, projectID, clusterID, nodeDeployment); err != nil {
-		return diag.FromErr(err)
+	diagnostics := metakubeResourceNodeDeploymentVersionCompatibleWithCluster(ctx, k, projectID, clusterID, nodeDeployment)
+	if diagnostics.HasError() {
+		return diagnostics
 	}
 
 	p := project.NewCreateMachineDeploymentParams().
@@ -133,6 +202,10 @@ func metakubeResourceNodeDeploymentCreate(ctx context.Context, d *schema.Resourc
 		return diag.Errorf("cluster is not ready: %v", err)
 	}
 
+	if err := metakubeResourceNodeDeploymentCheckQuota(ctx, k, projectID, clusterID, nodeDeployment); err != nil {
+		return diag.FromErr(err)
+	}
+
 	// Some cloud providers, like AWS, take some time to finish initializing.
 	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
 		p := project.NewListMachineDeploymentsParams().
@@ -159,7 +232,7 @@ func metakubeResourceNodeDeploymentCreate(ctx context.Context, d *schema.Resourc
 		r, err := k.client.Project.CreateMachineDeployment(p, k.auth)
 		if err != nil {
 			e := stringifyResponseError(err)
-			if strings.Contains(e, "failed calling webhook") || strings.Contains(e, "Cluster components are not ready yet") {
+			if isRateLimited(err) || strings.Contains(e, "failed calling webhook") || strings.Contains(e, "Cluster components are not ready yet") {
 				return resource.RetryableError(fmt.Errorf(e))
 			}
 			return resource.NonRetryableError(fmt.Errorf(e))
@@ -173,12 +246,13 @@ func metakubeResourceNodeDeploymentCreate(ctx context.Context, d *schema.Resourc
 	d.SetId(id)
 	d.Set("project_id", projectID)
 
-	if err := metakubeResourceNodeDeploymentWaitForReady(ctx, k, d.Timeout(schema.TimeoutCreate), projectID, clusterID, id); err != nil {
-		return diag.FromErr(err)
+	if d.Get("wait_for_rollout").(bool) {
+		if err := metakubeResourceNodeDeploymentWaitForReady(ctx, k, d.Timeout(schema.TimeoutCreate), projectID, clusterID, id); err != nil {
+			return append(diagnostics, metakubeResourceNodeDeploymentRolloutFailed(ctx, k, projectID, clusterID, id, err)...)
+		}
 	}
 
-	return metakubeResourceNodeDeploymentRead(ctx, d, m)
-
+	return append(diagnostics, metakubeResourceNodeDeploymentRead(ctx, d, m)...)
 }
 
 func metakubeResourceNodeDeploymentRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -193,7 +267,7 @@ func metakubeResourceNodeDeploymentRead(ctx context.Context, d *schema.ResourceD
 
 	r, err := k.client.Project.GetMachineDeployment(p, k.auth)
 	if err != nil {
-		if e, ok := err.(*project.GetMachineDeploymentDefault); ok && e.Code() == http.StatusNotFound {
+		if isNotFound(err) {
 			k.log.Infof("removing node deployment '%s' from terraform state file, could not find the resource", d.Id())
 			d.SetId("")
 			return nil
@@ -203,12 +277,35 @@ func metakubeResourceNodeDeploymentRead(ctx context.Context, d *schema.ResourceD
 			d.SetId("")
 			return nil
 		}
+		if !d.IsNewResource() && d.Get("spec.#").(int) > 0 && isTemporaryResponseError(err) {
+			k.log.Infof("keeping node deployment '%s' state, unable to get it: %s", d.Id(), stringifyResponseError(err))
+			_ = d.Set("status", []interface{}{})
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Unable to refresh node deployment '%s', status is unknown", d.Id()),
+				Detail:   stringifyResponseError(err),
+			}}
+		}
 		return diag.Errorf("unable to get node deployment '%s/%s/%s': %s", projectID, clusterID, d.Id(), stringifyResponseError(err))
 	}
 
 	_ = d.Set("name", r.Payload.Name)
 
-	_ = d.Set("spec", metakubeNodeDeploymentFlattenSpec(r.Payload.Spec))
+	// API does not know about propagate_labels, keep configured value and hide propagated tags.
+	propagateLabels := d.Get("spec.0.propagate_labels").(bool)
+	if propagateLabels && r.Payload.Spec != nil {
+		metakubeNodeDeploymentRemovePropagatedTags(r.Payload.Spec.Template, d.Get("spec.0.template.0.cloud.0.openstack.0.tags").(map[string]interface{}))
+	}
+	spec := metakubeNodeDeploymentFlattenSpec(r.Payload.Spec)
+	metakubeNodeDeploymentKeepDisabledSpotInstances(spec, d.Get("spec.0.template.0.cloud.0.aws.0.spot_instances.#").(int) > 0)
+	if len(spec) > 0 {
+		spec[0].(map[string]interface{})["propagate_labels"] = propagateLabels
+	}
+	_ = d.Set("spec", spec)
+
+	_ = d.Set("status", metakubeNodeDeploymentFlattenStatus(r.Payload.Status))
+
+	_ = d.Set("resolved_version", metakubeNodeDeploymentResolvedVersion(r.Payload.Spec))
 
 	_ = d.Set("creation_timestamp", r.Payload.CreationTimestamp.String())
 
@@ -226,8 +323,9 @@ func metakubeResourceNodeDeploymentUpdate(ctx context.Context, d *schema.Resourc
 		Spec: metakubeNodeDeploymentExpandSpec(d.Get("spec").([]interface{})),
 	}
 
-	if err := metakubeResourceNodeDeploymentVersionCompatibleWithCluster(ctx, k, projectID, clusterID, nodeDeployment); err != nil {
-		return diag.FromErr(err)
+	diagnostics := metakubeResourceNodeDeploymentVersionCompatibleWithCluster(ctx, k, projectID, clusterID, nodeDeployment)
+	if diagnostics.HasError() {
+		return diagnostics
 	}
 
 	p := project.NewPatchMachineDeploymentParams()
@@ -241,78 +339,164 @@ func metakubeResourceNodeDeploymentUpdate(ctx context.Context, d *schema.Resourc
 		return diag.Errorf("unable to update a node deployment: %v", stringifyResponseError(err))
 	}
 
-	if d.HasChange("spec.0.template.0.labels") {
-		// To delete a label key we have to send PATCH request with that key set to null.
-		// For simplicity we are doing it in a separate PATCH.
-
-		before, now := d.GetChange("spec.0.template.0.labels")
+	// To delete a label or tag key we have to send PATCH request with that key set to null.
+	// For simplicity we are doing it in a separate PATCH.
+	removedKeysPatch := make(map[string]interface{})
+	metakubeResourceNodeDeploymentRemovedKeysPatch(d, removedKeysPatch, "spec.0.template.0.labels", []string{"spec", "template", "labels"})
+	metakubeResourceNodeDeploymentRemovedKeysPatch(d, removedKeysPatch, "spec.0.template.0.cloud.0.openstack.0.tags", []string{"spec", "template", "cloud", "openstack", "tags"})
+	metakubeResourceNodeDeploymentRemovedPropagatedTagsPatch(d, removedKeysPatch)
+	if len(removedKeysPatch) > 0 {
+		if err := metakubeResourceNodeDeploymentSendPatch(ctx, d, k, removedKeysPatch); err != nil {
+			return diag.Errorf("unable to update a node deployment: %v", stringifyResponseError(err))
+		}
+	}
 
-		var beforeMap, nowMap map[string]interface{}
-		var ok bool
+	if patch := metakubeResourceNodeDeploymentDisabledFlagsPatch(d); len(patch) > 0 {
+		// Disabled flags are omitted from the request above, so we explicitly set them to false.
+		if err := metakubeResourceNodeDeploymentSendPatch(ctx, d, k, patch); err != nil {
+			return diag.Errorf("unable to update a node deployment: %v", stringifyResponseError(err))
+		}
+	}
 
-		if before != nil {
-			beforeMap, ok = before.(map[string]interface{})
-			if !ok {
-				return diag.Errorf("failed to apply labels change")
-			}
+	if d.Get("wait_for_rollout").(bool) {
+		if err := metakubeResourceNodeDeploymentWaitForReady(ctx, k, d.Timeout(schema.TimeoutUpdate), projectID, clusterID, d.Id()); err != nil {
+			return append(diagnostics, metakubeResourceNodeDeploymentRolloutFailed(ctx, k, projectID, clusterID, d.Id(), err)...)
 		}
+	}
+
+	return append(diagnostics, metakubeResourceNodeDeploymentRead(ctx, d, m)...)
+}
+
+func metakubeResourceNodeDeploymentSendPatch(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta, patch map[string]interface{}) error {
+	p := project.NewPatchMachineDeploymentParams()
+	p.SetContext(ctx)
+	p.SetProjectID(d.Get("project_id").(string))
+	p.SetClusterID(d.Get("cluster_id").(string))
+	p.SetMachineDeploymentID(d.Id())
+	p.SetPatch(&patch)
 
-		if now != nil {
-			nowMap, ok = now.(map[string]interface{})
-			if !ok {
-				return diag.Errorf("failed to apply labels change")
+	return resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		_, err := k.client.Project.PatchMachineDeployment(p, k.auth)
+		if err != nil {
+			if strings.Contains(stringifyResponseError(err), "the object has been modified") {
+				return resource.RetryableError(fmt.Errorf("machine deployment patch conflict: %v", err))
 			}
+			return resource.NonRetryableError(fmt.Errorf("patch machine deployment '%s': %v", d.Id(), err))
 		}
+		return nil
+	})
+}
 
-		labelsPatch := make(map[string]interface{})
-		for k := range beforeMap {
-			if _, ok := nowMap[k]; !ok {
-				labelsPatch[k] = nil
-			}
+// metakubeResourceNodeDeploymentRemovedKeysPatch sets keys removed from map attribute to null in patch.
+func metakubeResourceNodeDeploymentRemovedKeysPatch(d *schema.ResourceData, patch map[string]interface{}, key string, path []string) {
+	if !d.HasChange(key) {
+		return
+	}
+	before, now := d.GetChange(key)
+	beforeMap, _ := before.(map[string]interface{})
+	nowMap, _ := now.(map[string]interface{})
+	for k := range beforeMap {
+		if _, ok := nowMap[k]; !ok && !metakubeResourceSystemLabelOrTag(k) {
+			setPatchValue(patch, append(path, k), nil)
 		}
+	}
+}
 
-		if len(labelsPatch) > 0 {
-			patch := map[string]interface{}{
-				"spec": map[string]interface{}{
-					"template": map[string]interface{}{
-						"labels": labelsPatch,
-					},
-				},
-			}
-			p := project.NewPatchMachineDeploymentParams()
-			p.SetContext(ctx)
-			p.SetProjectID(projectID)
-			p.SetClusterID(clusterID)
-			p.SetMachineDeploymentID(d.Id())
-			p.SetPatch(&patch)
-
-			err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
-				_, err := k.client.Project.PatchMachineDeployment(p, k.auth)
-				if err != nil {
-					if strings.Contains(stringifyResponseError(err), "the object has been modified") {
-						return resource.RetryableError(fmt.Errorf("machine deployment patch conflict: %v", err))
-					}
-					return resource.NonRetryableError(fmt.Errorf("patch machine deployment '%s': %v", d.Id(), err))
-				}
-				return nil
-			})
-			if err != nil {
-				return diag.Errorf("unable to update a node deployment: %v", stringifyResponseError(err))
-			}
+// metakubeResourceNodeDeploymentRemovedPropagatedTagsPatch sets tags to null in patch, which were propagated from labels
+// that are removed or no longer propagated.
+func metakubeResourceNodeDeploymentRemovedPropagatedTagsPatch(d *schema.ResourceData, patch map[string]interface{}) {
+	if !d.HasChanges("spec.0.propagate_labels", "spec.0.template.0.labels") {
+		return
+	}
+	propagatedBefore, propagatedNow := d.GetChange("spec.0.propagate_labels")
+	if !propagatedBefore.(bool) {
+		return
+	}
+	labelsBefore, labelsNow := d.GetChange("spec.0.template.0.labels")
+	beforeMap, _ := labelsBefore.(map[string]interface{})
+	nowMap, _ := labelsNow.(map[string]interface{})
+	tags := d.Get("spec.0.template.0.cloud.0.openstack.0.tags").(map[string]interface{})
+	for k := range beforeMap {
+		if _, ok := nowMap[k]; ok && propagatedNow.(bool) {
+			continue
+		}
+		if _, ok := tags[k]; !ok && !metakubeResourceSystemLabelOrTag(k) {
+			setPatchValue(patch, []string{"spec", "template", "cloud", "openstack", "tags", k}, nil)
 		}
 	}
+}
 
-	if err := metakubeResourceNodeDeploymentWaitForReady(ctx, k, d.Timeout(schema.TimeoutUpdate), projectID, clusterID, d.Id()); err != nil {
-		return diag.FromErr(err)
+// metakubeResourceNodeDeploymentFlag describes a boolean field the API client omits when it is false.
+type metakubeResourceNodeDeploymentFlag struct {
+	// block is the list attribute the flag belongs to, the flag is only patched while the block is set.
+	block string
+	key   string
+	path  []string
+}
+
+var metakubeResourceNodeDeploymentFlags = []metakubeResourceNodeDeploymentFlag{
+	{
+		block: "spec",
+		key:   "spec.0.paused",
+		path:  []string{"spec", "paused"},
+	},
+	{
+		block: "spec",
+		key:   "spec.0.dynamic_config",
+		path:  []string{"spec", "dynamicConfig"},
+	},
+	{
+		block: "spec.0.template.0.operating_system.0.ubuntu",
+		key:   "spec.0.template.0.operating_system.0.ubuntu.0.dist_upgrade_on_boot",
+		path:  []string{"spec", "template", "operatingSystem", "ubuntu", "distUpgradeOnBoot"},
+	},
+	{
+		block: "spec.0.template.0.cloud.0.digitalocean",
+		key:   "spec.0.template.0.cloud.0.digitalocean.0.backups",
+		path:  []string{"spec", "template", "cloud", "digitalocean", "backups"},
+	},
+	{
+		block: "spec.0.template.0.cloud.0.digitalocean",
+		key:   "spec.0.template.0.cloud.0.digitalocean.0.ipv6",
+		path:  []string{"spec", "template", "cloud", "digitalocean", "ipv6"},
+	},
+	{
+		block: "spec.0.template.0.cloud.0.digitalocean",
+		key:   "spec.0.template.0.cloud.0.digitalocean.0.monitoring",
+		path:  []string{"spec", "template", "cloud", "digitalocean", "monitoring"},
+	},
+}
+
+func metakubeResourceNodeDeploymentDisabledFlagsPatch(d *schema.ResourceData) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for _, f := range metakubeResourceNodeDeploymentFlags {
+		if d.Get(f.block+".#").(int) == 0 || !d.HasChange(f.key) {
+			continue
+		}
+		if v, ok := d.Get(f.key).(bool); ok && !v {
+			setPatchValue(patch, f.path, false)
+		}
 	}
+	return patch
+}
 
-	return metakubeResourceNodeDeploymentRead(ctx, d, m)
+func setPatchValue(patch map[string]interface{}, path []string, value interface{}) {
+	m := patch
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
 }
 
-func metakubeResourceNodeDeploymentVersionCompatibleWithCluster(ctx context.Context, k *metakubeProviderMeta, projectID, clusterID string, ndepl *models.NodeDeployment) error {
+func metakubeResourceNodeDeploymentVersionCompatibleWithCluster(ctx context.Context, k *metakubeProviderMeta, projectID, clusterID string, ndepl *models.NodeDeployment) diag.Diagnostics {
 	cluster, _, err := metakubeGetCluster(ctx, projectID, clusterID, k)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	clusterVersion := cluster.Spec.Version.(string)
 
@@ -322,9 +506,70 @@ func metakubeResourceNodeDeploymentVersionCompatibleWithCluster(ctx context.Cont
 	}
 	err = validateVersionAgainstCluster(kubeletVersion, clusterVersion)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	if err := validateKubeletVersionIsAvailable(ctx, k, kubeletVersion, clusterVersion); err != nil {
+		return diag.FromErr(err)
 	}
-	return validateKubeletVersionIsAvailable(k, kubeletVersion, clusterVersion)
+
+	if !ndepl.Spec.DynamicConfig {
+		return nil
+	}
+	if kubeletVersion == "" {
+		kubeletVersion = clusterVersion
+	}
+	if warning := dynamicConfigDeprecationWarning(kubeletVersion); warning != "" {
+		return diag.Diagnostics{{
+			Severity:      diag.Warning,
+			Summary:       warning,
+			AttributePath: cty.GetAttrPath("spec").IndexInt(0).GetAttr("dynamic_config"),
+		}}
+	}
+	return nil
+}
+
+// metakubeNodeDeploymentGenerateName appends a random suffix to the prefix, like generateName of Kubernetes objects.
+func metakubeNodeDeploymentGenerateName(prefix string) string {
+	const (
+		alphabet  = "bcdfghjklmnpqrstvwxz2456789"
+		suffixLen = 5
+	)
+	suffix := make([]byte, suffixLen)
+	if _, err := rand.Read(suffix); err != nil {
+		// Fall back to a time based suffix, crypto/rand doesn't fail on supported platforms.
+		return metakubeNodeDeploymentUniqueName(prefix)
+	}
+	for i, b := range suffix {
+		suffix[i] = alphabet[int(b)%len(alphabet)]
+	}
+	return prefix + string(suffix)
+}
+
+// metakubeNodeDeploymentNameMaxLength is the longest node deployment name, names are used as label values.
+const metakubeNodeDeploymentNameMaxLength = 63
+
+// metakubeNodeDeploymentUniqueName appends a time based suffix to the prefix, truncating the prefix
+// so the name doesn't exceed the maximum length.
+func metakubeNodeDeploymentUniqueName(prefix string) string {
+	if max := metakubeNodeDeploymentNameMaxLength - resource.UniqueIDSuffixLength; len(prefix) > max {
+		prefix = prefix[:max]
+	}
+	return resource.PrefixedUniqueId(prefix)
+}
+
+// dynamicConfigDeprecationWarning returns a warning if kubelet dynamic config is deprecated or removed in given version.
+func dynamicConfigDeprecationWarning(kubeletVersion string) string {
+	v, err := version.NewVersion(kubeletVersion)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case v.GreaterThanOrEqual(version.Must(version.NewVersion("1.24.0"))):
+		return fmt.Sprintf("kubelet dynamic config is not supported by kubernetes %s, dynamic_config has no effect", kubeletVersion)
+	case v.GreaterThanOrEqual(version.Must(version.NewVersion("1.22.0"))):
+		return fmt.Sprintf("kubelet dynamic config is deprecated in kubernetes %s and removed in 1.24", kubeletVersion)
+	}
+	return ""
 }
 
 func validateVersionAgainstCluster(kubeletVersion, clusterVersion string) error {
@@ -349,14 +594,14 @@ func validateVersionAgainstCluster(kubeletVersion, clusterVersion string) error
 	return nil
 }
 
-func validateKubeletVersionIsAvailable(k *metakubeProviderMeta, kubeletVersion, clusterVersion string) error {
+func validateKubeletVersionIsAvailable(ctx context.Context, k *metakubeProviderMeta, kubeletVersion, clusterVersion string) error {
 	if kubeletVersion == "" {
 		return nil
 	}
 
 	versionType := "kubernetes"
 
-	p := versions.NewGetNodeUpgradesParams()
+	p := versions.NewGetNodeUpgradesParams().WithContext(ctx)
 	p.SetType(&versionType)
 	p.SetControlPlaneVersion(&clusterVersion)
 	r, err := k.client.Versions.GetNodeUpgrades(p, k.auth)
@@ -395,8 +640,17 @@ func metakubeResourceNodeDeploymentWaitForReady(ctx context.Context, k *metakube
 			return resource.RetryableError(fmt.Errorf("unable to get node deployment %s", stringifyResponseError(err)))
 		}
 
-		if r.Payload.Status.ReadyReplicas < *r.Payload.Spec.Replicas || r.Payload.Status.UnavailableReplicas != 0 {
-			k.log.Debugf("waiting for node deployment '%s' to be ready, %+v", id, r.Payload.Status)
+		if r.Payload.Spec != nil && r.Payload.Spec.Paused {
+			k.log.Debugf("node deployment '%s' is paused, skip waiting for rollout", id)
+			return nil
+		}
+
+		status := r.Payload.Status
+		if status == nil {
+			status = &models.MachineDeploymentStatus{}
+		}
+		if status.ReadyReplicas < *r.Payload.Spec.Replicas || status.UnavailableReplicas != 0 {
+			k.log.Debugf("waiting for node deployment '%s' to be ready, %+v", id, status)
 			return resource.RetryableError(fmt.Errorf("waiting for node deployment '%s' to be ready", id))
 		} else {
 			ensures++
@@ -409,6 +663,49 @@ func metakubeResourceNodeDeploymentWaitForReady(ctx context.Context, k *metakube
 	})
 }
 
+// metakubeResourceNodeDeploymentRolloutFailed returns diagnostics for failed rollout
+// including warning events of node deployment machines to help finding the cause.
+func metakubeResourceNodeDeploymentRolloutFailed(ctx context.Context, k *metakubeProviderMeta, projectID, clusterID, id string, rolloutErr error) diag.Diagnostics {
+	ret := diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("node deployment rollout failed: %v", rolloutErr),
+	}}
+
+	eventType := "warning"
+	p := project.NewListMachineDeploymentNodesEventsParams().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithClusterID(clusterID).
+		WithMachineDeploymentID(id).
+		WithType(&eventType)
+	r, err := k.client.Project.ListMachineDeploymentNodesEvents(p, k.auth)
+	if err != nil {
+		k.log.Debugf("unable to list node deployment '%s' events: %s", id, stringifyResponseError(err))
+		return ret
+	}
+
+	var events []string
+	seen := make(map[string]bool)
+	for _, e := range r.Payload {
+		if e == nil || e.Message == "" {
+			continue
+		}
+		msg := e.Message
+		if e.InvolvedObject != nil && e.InvolvedObject.Name != "" {
+			msg = fmt.Sprintf("%s/%s: %s", strings.ToLower(e.InvolvedObject.Type), e.InvolvedObject.Name, e.Message)
+		}
+		if seen[msg] {
+			continue
+		}
+		seen[msg] = true
+		events = append(events, msg)
+	}
+	if len(events) > 0 {
+		ret[0].Detail = fmt.Sprintf("Warning events of node deployment machines:\n%s", strings.Join(events, "\n"))
+	}
+	return ret
+}
+
 func metakubeResourceNodeDeploymentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	k := m.(*metakubeProviderMeta)
 	projectID := d.Get("project_id").(string)
@@ -420,7 +717,7 @@ func metakubeResourceNodeDeploymentDelete(ctx context.Context, d *schema.Resourc
 
 	_, err := k.client.Project.DeleteMachineDeployment(p, k.auth)
 	if err != nil {
-		if e, ok := err.(*project.DeleteMachineDeploymentDefault); ok && e.Code() == http.StatusNotFound {
+		if isNotFound(err) {
 			k.log.Infof("removing node deployment '%s' from terraform state file, could not find the resource", d.Id())
 			d.SetId("")
 			return nil
@@ -437,8 +734,8 @@ func metakubeResourceNodeDeploymentDelete(ctx context.Context, d *schema.Resourc
 
 		r, err := k.client.Project.GetMachineDeployment(p, k.auth)
 		if err != nil {
-			if e, ok := err.(*project.GetMachineDeploymentDefault); ok && e.Code() == http.StatusNotFound {
-				k.log.Debugf("node deployment '%s' has been destroyed, returned http code: %d", d.Id(), e.Code())
+			if isNotFound(err) {
+				k.log.Debugf("node deployment '%s' has been destroyed", d.Id())
 				d.SetId("")
 				return nil
 			}
@@ -20,6 +20,36 @@ func metakubeResourceSystemLabelOrTag(key string) bool {
 	return false
 }
 
+func metakubeResourceNodeDeploymentStatusFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"replicas": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of machines targeted by node deployment",
+		},
+		"ready_replicas": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of ready machines",
+		},
+		"available_replicas": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of available machines",
+		},
+		"updated_replicas": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of machines with the desired template",
+		},
+		"unavailable_replicas": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of machines still required for node deployment to be available",
+		},
+	}
+}
+
 func matakubeResourceNodeDeploymentSpecFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"dynamic_config": {
@@ -28,10 +58,23 @@ func matakubeResourceNodeDeploymentSpecFields() map[string]*schema.Schema {
 			Default:     false,
 			Description: "Enable metakube kubelete dynamic config",
 		},
+		"paused": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Pause rollout of node deployment, machines are not replaced while paused",
+		},
+		"propagate_labels": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Copy node labels to OpenStack instance tags, tags set explicitly take precedence",
+		},
 		"replicas": {
 			Type:          schema.TypeInt,
 			Optional:      true,
 			Default:       3,
+			ValidateFunc:  validation.IntAtLeast(0),
 			Description:   "Number of replicas",
 			ConflictsWith: []string{"spec.0.min_replicas", "spec.0.max_replicas"},
 			DiffSuppressFunc: func(_, _, n string, d *schema.ResourceData) bool {
@@ -67,27 +110,7 @@ func matakubeResourceNodeDeploymentSpecFields() map[string]*schema.Schema {
 						Required:    true,
 						Description: "Cloud specification",
 						Elem: &schema.Resource{
-							Schema: map[string]*schema.Schema{
-								"aws": {
-									Type:        schema.TypeList,
-									Optional:    true,
-									MaxItems:    1,
-									Description: "AWS node deployment specification",
-									Elem: &schema.Resource{
-										Schema: matakubeResourceNodeDeploymentAWSSchema(),
-									},
-								},
-								"openstack": {
-									Type:        schema.TypeList,
-									Optional:    true,
-									MaxItems:    1,
-									Description: "OpenStack node deployment specification",
-									Elem: &schema.Resource{
-										Schema: matakubeResourceNodeDeploymentCloudOpenstackSchema(),
-									},
-								},
-								"azure": metakubeResourceNodeDeploymentAzureSchema(),
-							},
+							Schema: metakubeResourceNodeDeploymentCloudSchema(),
 						},
 					},
 					"operating_system": {
@@ -252,6 +275,22 @@ func matakubeResourceNodeDeploymentAWSSchema() map[string]*schema.Schema {
 			Optional:    true,
 			Description: "Amazon Machine Image to use. Will be defaulted to an AMI of your selected operating system and region",
 		},
+		"spot_instances": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Use spot instances for nodes",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"enabled": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Request spot instances instead of on-demand instances",
+					},
+				},
+			},
+		},
 		"tags": {
 			Type:        schema.TypeMap,
 			Optional:    true,
@@ -300,7 +339,7 @@ func matakubeResourceNodeDeploymentCloudOpenstackSchema() map[string]*schema.Sch
 			Type:        schema.TypeMap,
 			Optional:    true,
 			Computed:    true,
-			Description: "Additional instance tags",
+			Description: "Additional instance tags, set as metadata of the instances",
 			Elem: &schema.Schema{
 				Type: schema.TypeString,
 			},
@@ -309,10 +348,17 @@ func matakubeResourceNodeDeploymentCloudOpenstackSchema() map[string]*schema.Sch
 			},
 			ValidateFunc: func(v interface{}, k string) (strings []string, errors []error) {
 				l := v.(map[string]interface{})
-				for key := range l {
+				for key, val := range l {
 					if metakubeResourceSystemLabelOrTag(key) {
 						errors = append(errors, fmt.Errorf("%s is reserved for system and can't be used", key))
 					}
+					// OpenStack limits instance metadata keys and values to 255 characters.
+					if len(key) > 255 {
+						errors = append(errors, fmt.Errorf("%s: key must not be longer than 255 characters", key))
+					}
+					if s, ok := val.(string); ok && len(s) > 255 {
+						errors = append(errors, fmt.Errorf("%s: value must not be longer than 255 characters", key))
+					}
 				}
 				return
 			},
@@ -363,6 +409,152 @@ func isNonEmptyDurationString(v interface{}, p cty.Path) diag.Diagnostics {
 	}
 }
 
+// metakubeNodeDeploymentCloudProviders lists cloud provider blocks of node deployment template.
+var metakubeNodeDeploymentCloudProviders = []string{"aws", "openstack", "azure", "hetzner", "digitalocean", "vsphere"}
+
+func metakubeResourceNodeDeploymentCloudSchema() map[string]*schema.Schema {
+	m := map[string]*schema.Schema{
+		"aws": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "AWS node deployment specification",
+			Elem: &schema.Resource{
+				Schema: matakubeResourceNodeDeploymentAWSSchema(),
+			},
+		},
+		"openstack": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "OpenStack node deployment specification",
+			Elem: &schema.Resource{
+				Schema: matakubeResourceNodeDeploymentCloudOpenstackSchema(),
+			},
+		},
+		"azure":        metakubeResourceNodeDeploymentAzureSchema(),
+		"hetzner":      metakubeResourceNodeDeploymentHetznerSchema(),
+		"digitalocean": metakubeResourceNodeDeploymentDigitaloceanSchema(),
+		"vsphere":      metakubeResourceNodeDeploymentVSphereSchema(),
+	}
+
+	exactlyOneOf := make([]string, 0, len(metakubeNodeDeploymentCloudProviders))
+	for _, p := range metakubeNodeDeploymentCloudProviders {
+		exactlyOneOf = append(exactlyOneOf, "spec.0.template.0.cloud.0."+p)
+	}
+	for _, p := range metakubeNodeDeploymentCloudProviders {
+		m[p].ExactlyOneOf = exactlyOneOf
+	}
+	return m
+}
+
+func metakubeResourceNodeDeploymentHetznerSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Hetzner node deployment specification",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "Server type",
+				},
+				"network": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Network name",
+				},
+			},
+		},
+	}
+}
+
+func metakubeResourceNodeDeploymentDigitaloceanSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "DigitalOcean node deployment specification",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"size": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "Droplet size slug",
+				},
+				"backups": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Enable backups for the droplet",
+				},
+				"ipv6": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Enable ipv6 for the droplet",
+				},
+				"monitoring": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Enable monitoring for the droplet",
+				},
+				"tags": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Additional droplet tags",
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+func metakubeResourceNodeDeploymentVSphereSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "vSphere node deployment specification",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cpus": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Number of virtual CPUs",
+				},
+				"memory": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Memory in MB",
+				},
+				"disk_size_gb": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Disk size in GB. Defaults to the size of the template disk",
+				},
+				"template": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "VM template name",
+				},
+			},
+		},
+	}
+}
+
 func metakubeResourceNodeDeploymentAzureSchema() *schema.Schema {
 	return &schema.Schema{
 		Type:        schema.TypeList,
@@ -380,7 +572,7 @@ func metakubeResourceNodeDeploymentAzureSchema() *schema.Schema {
 					Type:         schema.TypeString,
 					Required:     true,
 					ValidateFunc: validation.NoZeroValues,
-					Description:  "VM size",
+					Description:  "VM size, validated against sizes available for the cluster",
 				},
 				"assign_public_ip": {
 					Type:        schema.TypeBool,
@@ -30,9 +30,27 @@ func metakubeNodeDeploymentFlattenSpec(in *models.NodeDeploymentSpec) []interfac
 
 	att["dynamic_config"] = in.DynamicConfig
 
+	att["paused"] = in.Paused
+
 	return []interface{}{att}
 }
 
+func metakubeNodeDeploymentFlattenStatus(in *models.MachineDeploymentStatus) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"replicas":             in.Replicas,
+			"ready_replicas":       in.ReadyReplicas,
+			"available_replicas":   in.AvailableReplicas,
+			"updated_replicas":     in.UpdatedReplicas,
+			"unavailable_replicas": in.UnavailableReplicas,
+		},
+	}
+}
+
 func metakubeNodeDeploymentFlattenNodeSpec(in *models.NodeSpec) []interface{} {
 	if in == nil {
 		return []interface{}{}
@@ -127,6 +145,13 @@ func metakubeNodeDeploymentFlattenVersion(in *models.NodeVersionInfo) []interfac
 	return []interface{}{att}
 }
 
+func metakubeNodeDeploymentResolvedVersion(in *models.NodeDeploymentSpec) string {
+	if in == nil || in.Template == nil || in.Template.Versions == nil {
+		return ""
+	}
+	return in.Template.Versions.Kubelet
+}
+
 func metakubeNodeDeploymentFlattenTaintSpec(in *models.TaintSpec) map[string]interface{} {
 	if in == nil {
 		return map[string]interface{}{}
@@ -168,6 +193,18 @@ func metakubeNodeDeploymentFlattenCloudSpec(in *models.NodeCloudSpec) []interfac
 		att["azure"] = metakubeNodeDeploymentFlattenAzureSpec(in.Azure)
 	}
 
+	if in.Hetzner != nil {
+		att["hetzner"] = metakubeNodeDeploymentFlattenHetznerSpec(in.Hetzner)
+	}
+
+	if in.Digitalocean != nil {
+		att["digitalocean"] = metakubeNodeDeploymentFlattenDigitaloceanSpec(in.Digitalocean)
+	}
+
+	if in.Vsphere != nil {
+		att["vsphere"] = metakubeNodeDeploymentFlattenVSphereSpec(in.Vsphere)
+	}
+
 	return []interface{}{att}
 }
 
@@ -192,6 +229,14 @@ func metakubeNodeDeploymentFlattenAWSSpec(in *models.AWSNodeSpec) []interface{}
 		att["ami"] = in.AMI
 	}
 
+	if in.IsSpotInstance {
+		att["spot_instances"] = []interface{}{
+			map[string]interface{}{
+				"enabled": true,
+			},
+		}
+	}
+
 	if in.AvailabilityZone != "" {
 		att["availability_zone"] = in.AvailabilityZone
 	}
@@ -283,6 +328,78 @@ func metakubeNodeDeploymentFlattenAzureSpec(in *models.AzureNodeSpec) []interfac
 	return []interface{}{att}
 }
 
+func metakubeNodeDeploymentFlattenHetznerSpec(in *models.HetznerNodeSpec) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	att := make(map[string]interface{})
+
+	if in.Type != nil {
+		att["type"] = *in.Type
+	}
+
+	if in.Network != "" {
+		att["network"] = in.Network
+	}
+
+	return []interface{}{att}
+}
+
+func metakubeNodeDeploymentFlattenDigitaloceanSpec(in *models.DigitaloceanNodeSpec) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	att := make(map[string]interface{})
+
+	if in.Size != nil {
+		att["size"] = *in.Size
+	}
+
+	att["backups"] = in.Backups
+
+	att["ipv6"] = in.IPV6
+
+	att["monitoring"] = in.Monitoring
+
+	if len(in.Tags) > 0 {
+		t := make([]interface{}, len(in.Tags))
+		for i, v := range in.Tags {
+			t[i] = v
+		}
+		att["tags"] = t
+	}
+
+	return []interface{}{att}
+}
+
+func metakubeNodeDeploymentFlattenVSphereSpec(in *models.VSphereNodeSpec) []interface{} {
+	if in == nil {
+		return []interface{}{}
+	}
+
+	att := make(map[string]interface{})
+
+	if in.CPUs != 0 {
+		att["cpus"] = in.CPUs
+	}
+
+	if in.Memory != 0 {
+		att["memory"] = in.Memory
+	}
+
+	if in.DiskSizeGB != 0 {
+		att["disk_size_gb"] = in.DiskSizeGB
+	}
+
+	if in.Template != "" {
+		att["template"] = in.Template
+	}
+
+	return []interface{}{att}
+}
+
 // expanders
 
 func metakubeNodeDeploymentExpandSpec(p []interface{}) *models.NodeDeploymentSpec {
@@ -324,15 +441,96 @@ func metakubeNodeDeploymentExpandSpec(p []interface{}) *models.NodeDeploymentSpe
 		}
 	}
 
+	if v, ok := in["propagate_labels"]; ok {
+		if vv, ok := v.(bool); ok && vv {
+			metakubeNodeDeploymentPropagateLabels(obj.Template)
+		}
+	}
+
 	if v, ok := in["dynamic_config"]; ok {
 		if vv, ok := v.(bool); ok {
 			obj.DynamicConfig = vv
 		}
 	}
 
+	if v, ok := in["paused"]; ok {
+		if vv, ok := v.(bool); ok {
+			obj.Paused = vv
+		}
+	}
+
 	return obj
 }
 
+// metakubeNodeDeploymentPropagateLabels copies node labels to OpenStack instance tags, tags set explicitly take precedence.
+func metakubeNodeDeploymentPropagateLabels(in *models.NodeSpec) {
+	if in == nil || in.Cloud == nil || in.Cloud.Openstack == nil || len(in.Labels) == 0 {
+		return
+	}
+	if in.Cloud.Openstack.Tags == nil {
+		in.Cloud.Openstack.Tags = make(map[string]string, len(in.Labels))
+	}
+	for key, val := range in.Labels {
+		if _, ok := in.Cloud.Openstack.Tags[key]; !ok {
+			in.Cloud.Openstack.Tags[key] = val
+		}
+	}
+}
+
+// metakubeNodeDeploymentRemovePropagatedTags removes OpenStack instance tags copied from node labels,
+// so they don't show up as a diff of configured tags.
+func metakubeNodeDeploymentRemovePropagatedTags(in *models.NodeSpec, configured map[string]interface{}) {
+	if in == nil || in.Cloud == nil || in.Cloud.Openstack == nil {
+		return
+	}
+	for key, val := range in.Labels {
+		if _, ok := configured[key]; ok {
+			continue
+		}
+		if v, ok := in.Cloud.Openstack.Tags[key]; ok && v == val {
+			delete(in.Cloud.Openstack.Tags, key)
+		}
+	}
+}
+
+// metakubeNodeDeploymentKeepDisabledSpotInstances sets a configured spot_instances block on the flattened spec,
+// API only tells if spot instances are used, so a block with enabled set to false would read back as no block.
+func metakubeNodeDeploymentKeepDisabledSpotInstances(spec []interface{}, configured bool) {
+	if !configured {
+		return
+	}
+	aws := metakubeNodeDeploymentFlattenedAWSSpec(spec)
+	if aws == nil {
+		return
+	}
+	if _, ok := aws["spot_instances"]; !ok {
+		aws["spot_instances"] = []interface{}{
+			map[string]interface{}{
+				"enabled": false,
+			},
+		}
+	}
+}
+
+func metakubeNodeDeploymentFlattenedAWSSpec(spec []interface{}) map[string]interface{} {
+	ret := spec
+	for _, key := range []string{"template", "cloud", "aws"} {
+		if len(ret) == 0 {
+			return nil
+		}
+		m, ok := ret[0].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		ret, _ = m[key].([]interface{})
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	m, _ := ret[0].(map[string]interface{})
+	return m
+}
+
 func metakubeNodeDeploymentExpandNodeSpec(p []interface{}) *models.NodeSpec {
 	if len(p) < 1 {
 		return nil
@@ -544,6 +742,24 @@ func metakubeNodeDeploymentExpandCloudSpec(p []interface{}) *models.NodeCloudSpe
 		}
 	}
 
+	if v, ok := in["hetzner"]; ok {
+		if vv, ok := v.([]interface{}); ok {
+			obj.Hetzner = metakubeNodeDeploymentExpandHetznerSpec(vv)
+		}
+	}
+
+	if v, ok := in["digitalocean"]; ok {
+		if vv, ok := v.([]interface{}); ok {
+			obj.Digitalocean = metakubeNodeDeploymentExpandDigitaloceanSpec(vv)
+		}
+	}
+
+	if v, ok := in["vsphere"]; ok {
+		if vv, ok := v.([]interface{}); ok {
+			obj.Vsphere = metakubeNodeDeploymentExpandVSphereSpec(vv)
+		}
+	}
+
 	return obj
 }
 
@@ -603,6 +819,14 @@ func metakubeNodeDeploymentExpandAWSSpec(p []interface{}) *models.AWSNodeSpec {
 		}
 	}
 
+	if v, ok := in["spot_instances"]; ok {
+		if vv, ok := v.([]interface{}); ok && len(vv) > 0 {
+			if m, ok := vv[0].(map[string]interface{}); ok {
+				obj.IsSpotInstance, _ = m["enabled"].(bool)
+			}
+		}
+	}
+
 	if v, ok := in["tags"]; ok {
 		obj.Tags = make(map[string]string)
 		if vv, ok := v.(map[string]interface{}); ok {
@@ -714,26 +938,162 @@ func metakubeNodeDeploymentExpandAzureSpec(p []interface{}) *models.AzureNodeSpe
 	}
 
 	if v, ok := in["disk_size_gb"]; ok {
-		if vv, ok := v.(int32); ok {
-			obj.DataDiskSize = vv
+		if vv, ok := v.(int); ok {
+			obj.DataDiskSize = int32(vv)
 		}
 	}
 
 	if v, ok := in["os_disk_size_gb"]; ok {
-		if vv, ok := v.(int32); ok {
-			obj.OSDiskSize = vv
+		if vv, ok := v.(int); ok {
+			obj.OSDiskSize = int32(vv)
 		}
 	}
 
 	if v, ok := in["tags"]; ok {
-		if vv, ok := v.(map[string]string); ok {
-			obj.Tags = vv
+		if vv, ok := v.(map[string]interface{}); ok && len(vv) > 0 {
+			obj.Tags = make(map[string]string)
+			for key, val := range vv {
+				if s, ok := val.(string); ok && s != "" {
+					obj.Tags[key] = s
+				}
+			}
 		}
 	}
 
 	if v, ok := in["zones"]; ok {
-		if vv, ok := v.([]string); ok && len(vv) > 0 {
-			obj.Zones = vv
+		if vv, ok := v.([]interface{}); ok && len(vv) > 0 {
+			for _, z := range vv {
+				if s, ok := z.(string); ok && s != "" {
+					obj.Zones = append(obj.Zones, s)
+				}
+			}
+		}
+	}
+
+	return obj
+}
+
+func metakubeNodeDeploymentExpandHetznerSpec(p []interface{}) *models.HetznerNodeSpec {
+	if len(p) < 1 {
+		return nil
+	}
+
+	obj := &models.HetznerNodeSpec{}
+
+	if p[0] == nil {
+		return obj
+	}
+
+	in, ok := p[0].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+
+	if v, ok := in["type"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Type = strToPtr(vv)
+		}
+	}
+
+	if v, ok := in["network"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Network = vv
+		}
+	}
+
+	return obj
+}
+
+func metakubeNodeDeploymentExpandDigitaloceanSpec(p []interface{}) *models.DigitaloceanNodeSpec {
+	if len(p) < 1 {
+		return nil
+	}
+
+	obj := &models.DigitaloceanNodeSpec{}
+
+	if p[0] == nil {
+		return obj
+	}
+
+	in, ok := p[0].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+
+	if v, ok := in["size"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Size = strToPtr(vv)
+		}
+	}
+
+	if v, ok := in["backups"]; ok {
+		if vv, ok := v.(bool); ok {
+			obj.Backups = vv
+		}
+	}
+
+	if v, ok := in["ipv6"]; ok {
+		if vv, ok := v.(bool); ok {
+			obj.IPV6 = vv
+		}
+	}
+
+	if v, ok := in["monitoring"]; ok {
+		if vv, ok := v.(bool); ok {
+			obj.Monitoring = vv
+		}
+	}
+
+	if v, ok := in["tags"]; ok {
+		if vv, ok := v.([]interface{}); ok {
+			for _, t := range vv {
+				if tt, ok := t.(string); ok && tt != "" {
+					obj.Tags = append(obj.Tags, tt)
+				}
+			}
+		}
+	}
+
+	return obj
+}
+
+func metakubeNodeDeploymentExpandVSphereSpec(p []interface{}) *models.VSphereNodeSpec {
+	if len(p) < 1 {
+		return nil
+	}
+
+	obj := &models.VSphereNodeSpec{}
+
+	if p[0] == nil {
+		return obj
+	}
+
+	in, ok := p[0].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+
+	if v, ok := in["cpus"]; ok {
+		if vv, ok := v.(int); ok {
+			obj.CPUs = int64(vv)
+		}
+	}
+
+	if v, ok := in["memory"]; ok {
+		if vv, ok := v.(int); ok {
+			obj.Memory = int64(vv)
+		}
+	}
+
+	if v, ok := in["disk_size_gb"]; ok {
+		if vv, ok := v.(int); ok {
+			obj.DiskSizeGB = int64(vv)
+		}
+	}
+
+	if v, ok := in["template"]; ok {
+		if vv, ok := v.(string); ok && vv != "" {
+			obj.Template = vv
 		}
 	}
 
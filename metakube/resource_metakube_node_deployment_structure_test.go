@@ -17,19 +17,33 @@ func TestMetakubeNodeDeploymentFlatten(t *testing.T) {
 				Replicas:      int32ToPtr(1),
 				Template:      &models.NodeSpec{},
 				DynamicConfig: true,
+				Paused:        true,
 			},
 			[]interface{}{
 				map[string]interface{}{
 					"replicas":       int32(1),
 					"template":       []interface{}{map[string]interface{}{}},
 					"dynamic_config": true,
+					"paused":         true,
+				},
+			},
+		},
+		{
+			&models.NodeDeploymentSpec{
+				Replicas: int32ToPtr(0),
+			},
+			[]interface{}{
+				map[string]interface{}{
+					"replicas":       int32(0),
+					"dynamic_config": false,
+					"paused":         false,
 				},
 			},
 		},
 		{
 			&models.NodeDeploymentSpec{},
 			[]interface{}{
-				map[string]interface{}{"dynamic_config": false},
+				map[string]interface{}{"dynamic_config": false, "paused": false},
 			},
 		},
 		{
@@ -46,6 +60,78 @@ func TestMetakubeNodeDeploymentFlatten(t *testing.T) {
 	}
 }
 
+func TestMetakubeNodeDeploymentFlattenStatus(t *testing.T) {
+	cases := []struct {
+		Input          *models.MachineDeploymentStatus
+		ExpectedOutput []interface{}
+	}{
+		{
+			&models.MachineDeploymentStatus{
+				Replicas:            3,
+				ReadyReplicas:       2,
+				AvailableReplicas:   2,
+				UpdatedReplicas:     1,
+				UnavailableReplicas: 1,
+			},
+			[]interface{}{
+				map[string]interface{}{
+					"replicas":             int32(3),
+					"ready_replicas":       int32(2),
+					"available_replicas":   int32(2),
+					"updated_replicas":     int32(1),
+					"unavailable_replicas": int32(1),
+				},
+			},
+		},
+		{
+			nil,
+			[]interface{}{},
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentFlattenStatus(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestMetakubeNodeDeploymentResolvedVersion(t *testing.T) {
+	cases := []struct {
+		Input          *models.NodeDeploymentSpec
+		ExpectedOutput string
+	}{
+		{
+			&models.NodeDeploymentSpec{
+				Template: &models.NodeSpec{
+					Versions: &models.NodeVersionInfo{
+						Kubelet: "1.21.5",
+					},
+				},
+			},
+			"1.21.5",
+		},
+		{
+			&models.NodeDeploymentSpec{
+				Template: &models.NodeSpec{},
+			},
+			"",
+		},
+		{
+			nil,
+			"",
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentResolvedVersion(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
 func TestMetakubeNodeDeploymentSpecFlatten(t *testing.T) {
 	cases := []struct {
 		Input          *models.NodeSpec
@@ -223,8 +309,9 @@ func TestMetakubeNodeDeploymentFlattenAWSSpec(t *testing.T) {
 				Tags: map[string]string{
 					"foo": "bar",
 				},
-				VolumeSize: int64ToPtr(25),
-				VolumeType: strToPtr("standard"),
+				VolumeSize:     int64ToPtr(25),
+				VolumeType:     strToPtr("standard"),
+				IsSpotInstance: true,
 			},
 			[]interface{}{
 				map[string]interface{}{
@@ -238,6 +325,11 @@ func TestMetakubeNodeDeploymentFlattenAWSSpec(t *testing.T) {
 					},
 					"disk_size":   int64(25),
 					"volume_type": "standard",
+					"spot_instances": []interface{}{
+						map[string]interface{}{
+							"enabled": true,
+						},
+					},
 				},
 			},
 		},
@@ -318,6 +410,131 @@ func TestFlattenAzureNodeSpec(t *testing.T) {
 	}
 }
 
+func TestFlattenHetznerNodeSpec(t *testing.T) {
+	cases := []struct {
+		Input          *models.HetznerNodeSpec
+		ExpectedOutput []interface{}
+	}{
+		{
+			&models.HetznerNodeSpec{
+				Type:    strToPtr("cx21"),
+				Network: "Network",
+			},
+			[]interface{}{
+				map[string]interface{}{
+					"type":    "cx21",
+					"network": "Network",
+				},
+			},
+		},
+		{
+			&models.HetznerNodeSpec{},
+			[]interface{}{
+				map[string]interface{}{},
+			},
+		},
+		{
+			nil,
+			[]interface{}{},
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentFlattenHetznerSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestFlattenDigitaloceanNodeSpec(t *testing.T) {
+	cases := []struct {
+		Input          *models.DigitaloceanNodeSpec
+		ExpectedOutput []interface{}
+	}{
+		{
+			&models.DigitaloceanNodeSpec{
+				Size:       strToPtr("s-2vcpu-4gb"),
+				Backups:    true,
+				IPV6:       true,
+				Monitoring: true,
+				Tags:       []string{"tag"},
+			},
+			[]interface{}{
+				map[string]interface{}{
+					"size":       "s-2vcpu-4gb",
+					"backups":    true,
+					"ipv6":       true,
+					"monitoring": true,
+					"tags":       []interface{}{"tag"},
+				},
+			},
+		},
+		{
+			&models.DigitaloceanNodeSpec{},
+			[]interface{}{
+				map[string]interface{}{
+					"backups":    false,
+					"ipv6":       false,
+					"monitoring": false,
+				},
+			},
+		},
+		{
+			nil,
+			[]interface{}{},
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentFlattenDigitaloceanSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestFlattenVSphereNodeSpec(t *testing.T) {
+	cases := []struct {
+		Input          *models.VSphereNodeSpec
+		ExpectedOutput []interface{}
+	}{
+		{
+			&models.VSphereNodeSpec{
+				CPUs:       2,
+				Memory:     4096,
+				DiskSizeGB: 20,
+				Template:   "ubuntu-template",
+			},
+			[]interface{}{
+				map[string]interface{}{
+					"cpus":         int64(2),
+					"memory":       int64(4096),
+					"disk_size_gb": int64(20),
+					"template":     "ubuntu-template",
+				},
+			},
+		},
+		{
+			&models.VSphereNodeSpec{},
+			[]interface{}{
+				map[string]interface{}{},
+			},
+		},
+		{
+			nil,
+			[]interface{}{},
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentFlattenVSphereSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
 func TestFlattenOpenstackNodeSpec(t *testing.T) {
 	cases := []struct {
 		Input          *models.OpenstackNodeSpec
@@ -371,6 +588,56 @@ func TestFlattenOpenstackNodeSpec(t *testing.T) {
 	}
 }
 
+func TestMetakubeNodeDeploymentSpotInstancesRoundTrip(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		spotInstances := []interface{}{
+			map[string]interface{}{
+				"enabled": enabled,
+			},
+		}
+		input := []interface{}{
+			map[string]interface{}{
+				"template": []interface{}{
+					map[string]interface{}{
+						"cloud": []interface{}{
+							map[string]interface{}{
+								"aws": []interface{}{
+									map[string]interface{}{
+										"instance_type":  "t3.small",
+										"spot_instances": spotInstances,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		spec := metakubeNodeDeploymentExpandSpec(input)
+		if spec.Template.Cloud.Aws.IsSpotInstance != enabled {
+			t.Fatalf("Unexpected IsSpotInstance, want %v", enabled)
+		}
+		output := metakubeNodeDeploymentFlattenSpec(spec)
+		metakubeNodeDeploymentKeepDisabledSpotInstances(output, true)
+		if diff := cmp.Diff(spotInstances, metakubeNodeDeploymentFlattenedAWSSpec(output)["spot_instances"]); diff != "" {
+			t.Fatalf("Unexpected spot_instances with enabled=%v: mismatch (-want +got):\n%s", enabled, diff)
+		}
+	}
+
+	output := metakubeNodeDeploymentFlattenSpec(&models.NodeDeploymentSpec{
+		Template: &models.NodeSpec{
+			Cloud: &models.NodeCloudSpec{
+				Aws: &models.AWSNodeSpec{},
+			},
+		},
+	})
+	metakubeNodeDeploymentKeepDisabledSpotInstances(output, false)
+	if v, ok := metakubeNodeDeploymentFlattenedAWSSpec(output)["spot_instances"]; ok {
+		t.Fatalf("Unexpected spot_instances when not configured: %v", v)
+	}
+}
+
 func TestExpandNodeDeploymentSpec(t *testing.T) {
 	cases := []struct {
 		Input          []interface{}
@@ -382,12 +649,68 @@ func TestExpandNodeDeploymentSpec(t *testing.T) {
 					"replicas":       1,
 					"template":       []interface{}{map[string]interface{}{}},
 					"dynamic_config": true,
+					"paused":         true,
 				},
 			},
 			&models.NodeDeploymentSpec{
 				Replicas:      int32ToPtr(1),
 				Template:      &models.NodeSpec{},
 				DynamicConfig: true,
+				Paused:        true,
+			},
+		},
+		{
+			[]interface{}{
+				map[string]interface{}{
+					"replicas":         1,
+					"propagate_labels": true,
+					"template": []interface{}{
+						map[string]interface{}{
+							"labels": map[string]interface{}{
+								"role": "worker",
+								"team": "ops",
+							},
+							"cloud": []interface{}{
+								map[string]interface{}{
+									"openstack": []interface{}{
+										map[string]interface{}{
+											"tags": map[string]interface{}{
+												"team": "platform",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			&models.NodeDeploymentSpec{
+				Replicas: int32ToPtr(1),
+				Template: &models.NodeSpec{
+					Labels: map[string]string{
+						"role": "worker",
+						"team": "ops",
+					},
+					Cloud: &models.NodeCloudSpec{
+						Openstack: &models.OpenstackNodeSpec{
+							Tags: map[string]string{
+								"role": "worker",
+								"team": "platform",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			[]interface{}{
+				map[string]interface{}{
+					"replicas": 0,
+				},
+			},
+			&models.NodeDeploymentSpec{
+				Replicas: int32ToPtr(0),
 			},
 		},
 		{
@@ -411,6 +734,40 @@ func TestExpandNodeDeploymentSpec(t *testing.T) {
 	}
 }
 
+func TestMetakubeNodeDeploymentRemovePropagatedTags(t *testing.T) {
+	in := &models.NodeSpec{
+		Labels: map[string]string{
+			"role": "worker",
+			"team": "ops",
+			"zone": "a",
+		},
+		Cloud: &models.NodeCloudSpec{
+			Openstack: &models.OpenstackNodeSpec{
+				Tags: map[string]string{
+					"role":  "worker",
+					"team":  "ops",
+					"zone":  "b",
+					"owner": "platform",
+				},
+			},
+		},
+	}
+	configured := map[string]interface{}{
+		"team":  "ops",
+		"owner": "platform",
+	}
+	expected := map[string]string{
+		"team":  "ops",
+		"zone":  "b",
+		"owner": "platform",
+	}
+
+	metakubeNodeDeploymentRemovePropagatedTags(in, configured)
+	if diff := cmp.Diff(expected, in.Cloud.Openstack.Tags); diff != "" {
+		t.Fatalf("Unexpected tags: mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestExpandNodeSpec(t *testing.T) {
 	cases := []struct {
 		Input          []interface{}
@@ -588,6 +945,11 @@ func TestExpandAWSNodeSpec(t *testing.T) {
 					},
 					"disk_size":   25,
 					"volume_type": "standard",
+					"spot_instances": []interface{}{
+						map[string]interface{}{
+							"enabled": true,
+						},
+					},
 				},
 			},
 			&models.AWSNodeSpec{
@@ -599,8 +961,9 @@ func TestExpandAWSNodeSpec(t *testing.T) {
 				Tags: map[string]string{
 					"foo": "bar",
 				},
-				VolumeSize: int64ToPtr(25),
-				VolumeType: strToPtr("standard"),
+				VolumeSize:     int64ToPtr(25),
+				VolumeType:     strToPtr("standard"),
+				IsSpotInstance: true,
 			},
 		},
 		{
@@ -683,12 +1046,12 @@ func TestExpandAzureNodeSpec(t *testing.T) {
 					"image_id":         "ImageID",
 					"size":             "Size",
 					"assign_public_ip": false,
-					"disk_size_gb":     int32(1),
-					"os_disk_size_gb":  int32(2),
-					"tags": map[string]string{
+					"disk_size_gb":     1,
+					"os_disk_size_gb":  2,
+					"tags": map[string]interface{}{
 						"tag-k": "tag-v",
 					},
-					"zones": []string{"Zone-x"},
+					"zones": []interface{}{"Zone-x"},
 				},
 			},
 			&models.AzureNodeSpec{
@@ -723,3 +1086,124 @@ func TestExpandAzureNodeSpec(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandHetznerNodeSpec(t *testing.T) {
+	cases := []struct {
+		Input          []interface{}
+		ExpectedOutput *models.HetznerNodeSpec
+	}{
+		{
+			[]interface{}{
+				map[string]interface{}{
+					"type":    "cx21",
+					"network": "Network",
+				},
+			},
+			&models.HetznerNodeSpec{
+				Type:    strToPtr("cx21"),
+				Network: "Network",
+			},
+		},
+		{
+			[]interface{}{
+				map[string]interface{}{},
+			},
+			&models.HetznerNodeSpec{},
+		},
+		{
+			[]interface{}{},
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentExpandHetznerSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from expander: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestExpandDigitaloceanNodeSpec(t *testing.T) {
+	cases := []struct {
+		Input          []interface{}
+		ExpectedOutput *models.DigitaloceanNodeSpec
+	}{
+		{
+			[]interface{}{
+				map[string]interface{}{
+					"size":       "s-2vcpu-4gb",
+					"backups":    true,
+					"ipv6":       true,
+					"monitoring": true,
+					"tags":       []interface{}{"tag"},
+				},
+			},
+			&models.DigitaloceanNodeSpec{
+				Size:       strToPtr("s-2vcpu-4gb"),
+				Backups:    true,
+				IPV6:       true,
+				Monitoring: true,
+				Tags:       []string{"tag"},
+			},
+		},
+		{
+			[]interface{}{
+				map[string]interface{}{},
+			},
+			&models.DigitaloceanNodeSpec{},
+		},
+		{
+			[]interface{}{},
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentExpandDigitaloceanSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from expander: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestExpandVSphereNodeSpec(t *testing.T) {
+	cases := []struct {
+		Input          []interface{}
+		ExpectedOutput *models.VSphereNodeSpec
+	}{
+		{
+			[]interface{}{
+				map[string]interface{}{
+					"cpus":         2,
+					"memory":       4096,
+					"disk_size_gb": 20,
+					"template":     "ubuntu-template",
+				},
+			},
+			&models.VSphereNodeSpec{
+				CPUs:       2,
+				Memory:     4096,
+				DiskSizeGB: 20,
+				Template:   "ubuntu-template",
+			},
+		},
+		{
+			[]interface{}{
+				map[string]interface{}{},
+			},
+			&models.VSphereNodeSpec{},
+		},
+		{
+			[]interface{}{},
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentExpandVSphereSpec(tc.Input)
+		if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+			t.Fatalf("Unexpected output from expander: mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
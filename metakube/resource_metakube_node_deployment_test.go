@@ -1,17 +1,156 @@
 package metakube
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/syseleven/go-metakube/client/project"
 	"github.com/syseleven/go-metakube/models"
 )
 
+func testSweepNodeDeployments(region string) error {
+	meta, err := sharedConfigForRegion(region)
+	if err != nil {
+		return err
+	}
+
+	projectID := os.Getenv(testEnvProjectID)
+	clusters, err := meta.client.Project.ListClustersV2(project.NewListClustersV2Params().WithProjectID(projectID), meta.auth)
+	if err != nil {
+		return fmt.Errorf("sweep list clusters: %s", stringifyResponseError(err))
+	}
+
+	for _, cluster := range clusters.Payload {
+		if !time.Time(cluster.DeletionTimestamp).IsZero() {
+			continue
+		}
+
+		p := project.NewListMachineDeploymentsParams().WithProjectID(projectID).WithClusterID(cluster.ID)
+		records, err := meta.client.Project.ListMachineDeployments(p, meta.auth)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("sweep list node deployments: %s", stringifyResponseError(err))
+		}
+
+		for _, rec := range records.Payload {
+			if !strings.HasPrefix(rec.Name, testNamePrefix) || !time.Time(rec.DeletionTimestamp).IsZero() {
+				continue
+			}
+
+			p := project.NewDeleteMachineDeploymentParams().
+				WithProjectID(projectID).
+				WithClusterID(cluster.ID).
+				WithMachineDeploymentID(rec.ID)
+			if _, err := meta.client.Project.DeleteMachineDeployment(p, meta.auth); err != nil {
+				if isNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("delete node deployment: %v", stringifyResponseError(err))
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestMetakubeResourceNodeDeploymentDisabledFlagsPatch(t *testing.T) {
+	ubuntu := func(distUpgradeOnBoot bool) map[string]interface{} {
+		return map[string]interface{}{
+			"cluster_id": "cluster-id",
+			"spec": []interface{}{
+				map[string]interface{}{
+					"replicas": 1,
+					"template": []interface{}{
+						map[string]interface{}{
+							"operating_system": []interface{}{
+								map[string]interface{}{
+									"ubuntu": []interface{}{
+										map[string]interface{}{
+											"dist_upgrade_on_boot": distUpgradeOnBoot,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	cases := []struct {
+		Name          string
+		Old           map[string]interface{}
+		New           map[string]interface{}
+		ExpectedPatch map[string]interface{}
+	}{
+		{
+			"disabled",
+			ubuntu(true),
+			ubuntu(false),
+			map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"operatingSystem": map[string]interface{}{
+							"ubuntu": map[string]interface{}{
+								"distUpgradeOnBoot": false,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			"unchanged disabled",
+			ubuntu(false),
+			ubuntu(false),
+			map[string]interface{}{},
+		},
+		{
+			"unchanged enabled",
+			ubuntu(true),
+			ubuntu(true),
+			map[string]interface{}{},
+		},
+		{
+			"enabled",
+			ubuntu(false),
+			ubuntu(true),
+			map[string]interface{}{},
+		},
+	}
+
+	r := metakubeResourceNodeDeployment()
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			old := schema.TestResourceDataRaw(t, r.Schema, tc.Old)
+			old.SetId("node-deployment-id")
+			state := old.State()
+			diff, err := schema.InternalMap(r.Schema).Diff(context.Background(), state, terraform.NewResourceConfigRaw(tc.New), nil, nil, false)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			d, err := schema.InternalMap(r.Schema).Data(state, diff)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.ExpectedPatch, metakubeResourceNodeDeploymentDisabledFlagsPatch(d)); diff != "" {
+				t.Fatalf("Unexpected patch: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestAccMetakubeNodeDeployment_Openstack_Basic(t *testing.T) {
 	var ndepl models.NodeDeployment
 	testName := makeRandomName()
@@ -42,11 +181,15 @@ func TestAccMetakubeNodeDeployment_Openstack_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "name", testName),
 					resource.TestCheckResourceAttrPtr(resourceName, "name", &ndepl.Name),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.replicas", "1"),
+					resource.TestCheckResourceAttr(resourceName, "status.0.ready_replicas", "1"),
+					resource.TestCheckResourceAttr(resourceName, "status.0.updated_replicas", "1"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.labels.%", "4"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.labels.a", "b"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.labels.c", "d"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.flavor", flavor),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.image", image),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.tags.owner", "acctest"),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.tags.cost-center", "42"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.operating_system.0.ubuntu.#", "1"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.versions.0.kubelet", k8sVersionOld),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.dynamic_config", "false"),
@@ -70,6 +213,8 @@ func TestAccMetakubeNodeDeployment_Openstack_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.labels.foo", "bar"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.flavor", flavor),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.image", image2),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.tags.owner", "acctest"),
+					resource.TestCheckNoResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.tags.cost-center"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.use_floating_ip", "true"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.cloud.0.openstack.0.disk_size", "8"),
 					resource.TestCheckResourceAttr(resourceName, "spec.0.template.0.operating_system.0.ubuntu.0.dist_upgrade_on_boot", "true"),
@@ -78,7 +223,7 @@ func TestAccMetakubeNodeDeployment_Openstack_Basic(t *testing.T) {
 				),
 			},
 			{
-				Config: testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, username, password, tenant, k8sVersionNew, k8sVersionNew, imageFlatcar, flavor),
+				Config: testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, username, password, tenant, k8sVersionNew, k8sVersionNew, imageFlatcar, flavor, 1),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					testResourceInstanceState(resourceName, func(is *terraform.InstanceState) error {
 						// Record IDs to test import
@@ -102,7 +247,7 @@ func TestAccMetakubeNodeDeployment_Openstack_Basic(t *testing.T) {
 				),
 			},
 			{
-				Config:   testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, username, password, tenant, k8sVersionNew, k8sVersionNew, imageFlatcar, flavor),
+				Config:   testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, username, password, tenant, k8sVersionNew, k8sVersionNew, imageFlatcar, flavor, 1),
 				PlanOnly: true,
 			},
 			{
@@ -127,6 +272,18 @@ func TestAccMetakubeNodeDeployment_Openstack_Basic(t *testing.T) {
 				ImportStateId:     "a:b:123abc",
 				ExpectError:       regexp.MustCompile(`(Please verify the ID is correct|Cannot import non-existent remote object)`),
 			},
+			// Test scaling to zero is stable.
+			{
+				Config: testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, username, password, tenant, k8sVersionNew, k8sVersionNew, imageFlatcar, flavor, 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeNodeDeploymentExists(resourceName, &ndepl),
+					resource.TestCheckResourceAttr(resourceName, "spec.0.replicas", "0"),
+				),
+			},
+			{
+				Config:   testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, username, password, tenant, k8sVersionNew, k8sVersionNew, imageFlatcar, flavor, 0),
+				PlanOnly: true,
+			},
 		},
 	})
 }
@@ -172,6 +329,10 @@ func testAccCheckMetaKubeNodeDeploymentBasic(projectID, testName, nodeDC, userna
 						use_floating_ip = false
 						instance_ready_check_period = "10s"
 						instance_ready_check_timeout = "4m"
+						tags = {
+							"owner" = "acctest"
+							"cost-center" = "42"
+						}
 					}
 				}
 				operating_system {
@@ -225,6 +386,9 @@ func testAccCheckMetaKubeNodeDeploymentBasic2(projectID, testName, nodeDC, usern
 						use_floating_ip = true
 						instance_ready_check_period = "10s"
 						instance_ready_check_timeout = "4m"
+						tags = {
+							"owner" = "acctest"
+						}
 					}
 				}
 				operating_system {
@@ -240,7 +404,7 @@ func testAccCheckMetaKubeNodeDeploymentBasic2(projectID, testName, nodeDC, usern
 	}`, projectID, testName, nodeDC, clusterVersion, tenant, username, password, testName, flavor, image, kubeletVersion)
 }
 
-func testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, username, password, tenant, clusterVersion, kubeletVersion, image, flavor string) string {
+func testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, username, password, tenant, clusterVersion, kubeletVersion, image, flavor string, replicas int) string {
 	return fmt.Sprintf(`
 	resource "metakube_cluster" "acctest_cluster" {
 		project_id = "%s"
@@ -267,7 +431,7 @@ func testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, usern
 		name = "%s"
 		spec {
 			dynamic_config = true
-			replicas = 1
+			replicas = %d
 			template {
 				labels = {
 					"foo" = "bar"
@@ -292,7 +456,7 @@ func testAccCheckMetaKubeNodeDeploymentBasic3(projectID, testName, nodeDC, usern
 				}
 			}
 		}
-	}`, projectID, testName, nodeDC, clusterVersion, tenant, username, password, testName, flavor, image, kubeletVersion)
+	}`, projectID, testName, nodeDC, clusterVersion, tenant, username, password, testName, replicas, flavor, image, kubeletVersion)
 }
 
 func testAccCheckMetaKubeNodeDeploymentDestroy(s *terraform.State) error {
@@ -3,9 +3,15 @@ package metakube
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/syseleven/go-metakube/client/aws"
+	"github.com/syseleven/go-metakube/client/azure"
+	"github.com/syseleven/go-metakube/client/digitalocean"
+	"github.com/syseleven/go-metakube/client/openstack"
 	"github.com/syseleven/go-metakube/client/project"
 	"github.com/syseleven/go-metakube/models"
 )
@@ -45,6 +51,12 @@ func getClusterCloudProvider(c *models.Cluster) (string, error) {
 		return "openstack", nil
 	case c.Spec.Cloud.Azure != nil:
 		return "azure", nil
+	case c.Spec.Cloud.Hetzner != nil:
+		return "hetzner", nil
+	case c.Spec.Cloud.Digitalocean != nil:
+		return "digitalocean", nil
+	case c.Spec.Cloud.Vsphere != nil:
+		return "vsphere", nil
 	default:
 		return "", fmt.Errorf("could not find cloud provider for cluster")
 
@@ -52,10 +64,9 @@ func getClusterCloudProvider(c *models.Cluster) (string, error) {
 }
 
 func validateProviderMatchesCluster(d *schema.ResourceDiff, clusterProvider string) error {
-	var availableProviders = []string{"aws", "openstack", "azure"}
 	var provider string
 
-	for _, p := range availableProviders {
+	for _, p := range metakubeNodeDeploymentCloudProviders {
 		providerField := fmt.Sprintf("spec.0.template.0.cloud.0.%s", p)
 		_, ok := d.GetOk(providerField)
 		if ok {
@@ -76,7 +87,7 @@ func metakubeGetCluster(ctx context.Context, proj, cls string, k *metakubeProvid
 		WithClusterID(cls)
 	r, err := k.client.Project.GetClusterV2(p, k.auth)
 	if err != nil {
-		if e, ok := err.(*project.GetClusterV2Default); ok && e.Code() == http.StatusNotFound {
+		if isNotFound(err) {
 			return nil, false, nil
 		}
 		return nil, false, fmt.Errorf("unable to get cluster %s in project %s - error: %v", cls, proj, err)
@@ -113,3 +124,331 @@ func validateAutoscalerFields() schema.CustomizeDiffFunc {
 		return nil
 	}
 }
+
+func validatePropagateLabels() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if !d.Get("spec.0.propagate_labels").(bool) {
+			return nil
+		}
+		if _, ok := d.GetOk("spec.0.template.0.cloud.0.openstack"); !ok {
+			return fmt.Errorf("propagate_labels is only supported for openstack node deployments")
+		}
+		labels, _ := d.Get("spec.0.template.0.labels").(map[string]interface{})
+		if errs := metakubeNodeDeploymentPropagatedLabelErrors(labels); len(errs) > 0 {
+			return fmt.Errorf("labels can't be propagated to instance tags: %s", strings.Join(errs, ", "))
+		}
+		return nil
+	}
+}
+
+// openstackMetadataKeyRegexp matches keys accepted by OpenStack compute for instance metadata.
+var openstackMetadataKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9-_:. ]{1,255}$`)
+
+// metakubeNodeDeploymentPropagatedLabelErrors describes labels which are not valid as both Kubernetes labels and OpenStack metadata.
+func metakubeNodeDeploymentPropagatedLabelErrors(labels map[string]interface{}) []string {
+	var ret []string
+	for _, v := range validateLabelMap(labels, nil) {
+		ret = append(ret, v.Summary)
+	}
+	for key := range labels {
+		if !openstackMetadataKeyRegexp.MatchString(key) {
+			ret = append(ret, fmt.Sprintf("invalid metadata key '%s': must be 255 characters or less and contain only alphanumerics, '-', '_', ':', '.' or ' '", key))
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func validateNodeDeploymentNameUnique() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		k := meta.(*metakubeProviderMeta)
+		name := d.Get("name").(string)
+		clusterID := d.Get("cluster_id").(string)
+		if k.skipLiveValidation || name == "" || clusterID == "" || !d.HasChange("name") {
+			return nil
+		}
+		projectID := d.Get("project_id").(string)
+		if projectID == "" {
+			var err error
+			projectID, err = metakubeResourceClusterFindProjectID(ctx, clusterID, k)
+			if err != nil || projectID == "" {
+				k.log.Debugf("skip node deployment name validation, project of cluster '%s' not found: %v", clusterID, err)
+				return nil
+			}
+		}
+
+		p := project.NewListMachineDeploymentsParams().
+			WithContext(ctx).
+			WithProjectID(projectID).
+			WithClusterID(clusterID)
+		r, err := k.client.Project.ListMachineDeployments(p, k.auth)
+		if err != nil {
+			k.log.Debugf("skip node deployment name validation, unable to list node deployments: %s", stringifyResponseError(err))
+			return nil
+		}
+
+		var names []string
+		taken := false
+		for _, v := range r.Payload {
+			if v == nil {
+				continue
+			}
+			names = append(names, v.Name)
+			if v.Name == name && v.ID != d.Id() {
+				taken = true
+			}
+		}
+		if taken {
+			return fmt.Errorf("node deployment with name '%s' already exists in cluster, existing node deployments: %v", name, names)
+		}
+		return nil
+	}
+}
+
+func validateDigitaloceanSize() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		size, ok := d.GetOk("spec.0.template.0.cloud.0.digitalocean.0.size")
+		if !ok {
+			return nil
+		}
+		k := meta.(*metakubeProviderMeta)
+		clusterID := d.Get("cluster_id").(string)
+		projectID := d.Get("project_id").(string)
+		if k.skipLiveValidation || clusterID == "" || projectID == "" {
+			return nil
+		}
+
+		p := digitalocean.NewListDigitaloceanSizesNoCredentialsV2Params().
+			WithContext(ctx).
+			WithProjectID(projectID).
+			WithClusterID(clusterID)
+		r, err := k.client.Digitalocean.ListDigitaloceanSizesNoCredentialsV2(p, k.auth)
+		if err != nil {
+			// Sizes are only used for validation, API will reject wrong size anyway.
+			k.log.Debugf("skip digitalocean size validation, unable to list sizes: %s", stringifyResponseError(err))
+			return nil
+		}
+
+		var available []string
+		for _, l := range [][]*models.DigitaloceanSize{r.Payload.Standard, r.Payload.Optimized} {
+			for _, v := range l {
+				if v == nil || !v.Available {
+					continue
+				}
+				if v.Slug == size.(string) {
+					return nil
+				}
+				available = append(available, v.Slug)
+			}
+		}
+		return fmt.Errorf("unknown digitalocean size '%s', available sizes: %v", size, available)
+	}
+}
+
+func validateAzureSize() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		size, ok := d.GetOk("spec.0.template.0.cloud.0.azure.0.size")
+		if !ok {
+			return nil
+		}
+		k := meta.(*metakubeProviderMeta)
+		clusterID := d.Get("cluster_id").(string)
+		projectID := d.Get("project_id").(string)
+		if k.skipLiveValidation || clusterID == "" || projectID == "" {
+			return nil
+		}
+
+		p := azure.NewListAzureSizesNoCredentialsV2Params().
+			WithContext(ctx).
+			WithProjectID(projectID).
+			WithClusterID(clusterID)
+		r, err := k.client.Azure.ListAzureSizesNoCredentialsV2(p, k.auth)
+		if err != nil {
+			// Sizes are only used for validation, API will reject wrong size anyway.
+			k.log.Debugf("skip azure size validation, unable to list sizes: %s", stringifyResponseError(err))
+			return nil
+		}
+
+		var available []string
+		for _, v := range r.Payload {
+			if v == nil {
+				continue
+			}
+			if v.Name == size.(string) {
+				return nil
+			}
+			available = append(available, v.Name)
+		}
+		return fmt.Errorf("unknown azure size '%s', available sizes: %v", size, available)
+	}
+}
+
+func validateAWSInstanceTypeAndSubnet() schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if _, ok := d.GetOk("spec.0.template.0.cloud.0.aws"); !ok {
+			return nil
+		}
+		k := meta.(*metakubeProviderMeta)
+		clusterID := d.Get("cluster_id").(string)
+		projectID := d.Get("project_id").(string)
+		if k.skipLiveValidation || clusterID == "" || projectID == "" {
+			return nil
+		}
+
+		instanceType := d.Get("spec.0.template.0.cloud.0.aws.0.instance_type").(string)
+		if instanceType != "" {
+			sizesParams := aws.NewListAWSSizesNoCredentialsV2Params().
+				WithContext(ctx).
+				WithProjectID(projectID).
+				WithClusterID(clusterID)
+			if r, err := k.client.Aws.ListAWSSizesNoCredentialsV2(sizesParams, k.auth); err != nil {
+				// Sizes are only used for validation, API will reject wrong instance type anyway.
+				k.log.Debugf("skip aws instance type validation, unable to list sizes: %s", stringifyResponseError(err))
+			} else {
+				var available []string
+				found := false
+				for _, v := range r.Payload {
+					if v == nil {
+						continue
+					}
+					if v.Name == instanceType {
+						found = true
+						break
+					}
+					available = append(available, v.Name)
+				}
+				if !found {
+					return fmt.Errorf("unknown aws instance type '%s', available instance types: %v", instanceType, available)
+				}
+			}
+		}
+
+		subnetID := d.Get("spec.0.template.0.cloud.0.aws.0.subnet_id").(string)
+		if subnetID == "" {
+			return nil
+		}
+		availabilityZone := d.Get("spec.0.template.0.cloud.0.aws.0.availability_zone").(string)
+		subnetsParams := aws.NewListAWSSubnetsNoCredentialsV2Params().
+			WithContext(ctx).
+			WithProjectID(projectID).
+			WithClusterID(clusterID)
+		r, err := k.client.Aws.ListAWSSubnetsNoCredentialsV2(subnetsParams, k.auth)
+		if err != nil {
+			k.log.Debugf("skip aws subnet validation, unable to list subnets: %s", stringifyResponseError(err))
+			return nil
+		}
+		var available []string
+		for _, v := range r.Payload {
+			if v == nil {
+				continue
+			}
+			if v.ID == subnetID {
+				if availabilityZone != "" && v.AvailabilityZone != availabilityZone {
+					return fmt.Errorf("aws subnet '%s' is in availability zone '%s', not in '%s'", subnetID, v.AvailabilityZone, availabilityZone)
+				}
+				return nil
+			}
+			available = append(available, v.ID)
+		}
+		return fmt.Errorf("unknown aws subnet '%s', available subnets: %v", subnetID, available)
+	}
+}
+
+// metakubeResourceNodeDeploymentCheckQuota checks that OpenStack project quota allows to create nodes of node deployment.
+// Credentials are only known to the cluster, so flavors and quota are listed by cluster without credentials.
+func metakubeResourceNodeDeploymentCheckQuota(ctx context.Context, k *metakubeProviderMeta, projectID, clusterID string, nd *models.NodeDeployment) error {
+	if k.skipLiveValidation || nd.Spec == nil || nd.Spec.Template == nil || nd.Spec.Template.Cloud == nil || nd.Spec.Template.Cloud.Openstack == nil {
+		return nil
+	}
+	flavor := nd.Spec.Template.Cloud.Openstack.Flavor
+	if flavor == nil {
+		return nil
+	}
+
+	cluster, ok, err := metakubeGetCluster(ctx, projectID, clusterID, k)
+	if err != nil || !ok || cluster.Spec == nil || cluster.Spec.Cloud == nil {
+		k.log.Warnf("skip quota check, unable to get cluster: %v", err)
+		return nil
+	}
+	seed, err := metakubeGetDatacenterSeed(ctx, k, cluster.Spec.Cloud.DatacenterName)
+	if err != nil {
+		k.log.Warnf("skip quota check: %v", err)
+		return nil
+	}
+
+	sizesParams := openstack.NewListOpenstackSizesNoCredentialsV2Params().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithClusterID(clusterID)
+	sizes, err := k.client.Openstack.ListOpenstackSizesNoCredentialsV2(sizesParams, k.auth)
+	if err != nil {
+		k.log.Warnf("skip quota check, unable to list flavors: %s", stringifyResponseError(err))
+		return nil
+	}
+	var size *models.OpenstackSize
+	for _, v := range sizes.Payload {
+		if v != nil && v.Slug == *flavor {
+			size = v
+			break
+		}
+	}
+	if size == nil {
+		k.log.Warnf("skip quota check, unknown flavor '%s'", *flavor)
+		return nil
+	}
+
+	quotaParams := openstack.NewListOpenstackQuotaLimitsNoCredentialsParams().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithDC(seed).
+		WithClusterID(clusterID)
+	quota, err := k.client.Openstack.ListOpenstackQuotaLimitsNoCredentials(quotaParams, k.auth)
+	if err != nil {
+		k.log.Warnf("skip quota check, unable to get quota: %s", stringifyResponseError(err))
+		return nil
+	}
+	if quota.Payload == nil || quota.Payload.Limits == nil {
+		k.log.Warnf("skip quota check, no quota limits reported for cluster '%s'", clusterID)
+		return nil
+	}
+
+	replicas := int64(nd.Spec.MinReplicas)
+	if nd.Spec.Replicas != nil {
+		replicas = int64(*nd.Spec.Replicas)
+	}
+	if exceeded := metakubeNodeDeploymentQuotaExceeded(quota.Payload.Limits.Absolute, size, replicas); len(exceeded) > 0 {
+		return fmt.Errorf("not enough quota in OpenStack project for %d nodes of flavor %s: %s", replicas, *flavor, strings.Join(exceeded, ", "))
+	}
+	return nil
+}
+
+// metakubeNodeDeploymentQuotaExceeded returns descriptions of quota limits exceeded by given nodes.
+// Negative limit means unlimited.
+func metakubeNodeDeploymentQuotaExceeded(limits *models.Absolute, size *models.OpenstackSize, replicas int64) []string {
+	if limits == nil || size == nil {
+		return nil
+	}
+	var ret []string
+	check := func(resource string, max, used, requested int64) {
+		if max >= 0 && used+requested > max {
+			ret = append(ret, fmt.Sprintf("%s requested %d, available %d", resource, requested, max-used))
+		}
+	}
+	check("instances", limits.MaxTotalInstances, limits.TotalInstancesUsed, replicas)
+	check("vCPUs", limits.MaxTotalCores, limits.TotalCoresUsed, replicas*size.VCPUs)
+	check("RAM (MB)", limits.MaxTotalRAMSize, limits.TotalRAMUsed, replicas*size.Memory)
+	return ret
+}
+
+func metakubeGetDatacenterSeed(ctx context.Context, k *metakubeProviderMeta, name string) (string, error) {
+	datacenters, err := metakubeListDatacenters(ctx, k)
+	if err != nil {
+		return "", err
+	}
+	for _, dc := range datacenters {
+		if dc != nil && dc.Metadata != nil && dc.Spec != nil && dc.Metadata.Name == name && dc.Spec.Seed != "" {
+			return dc.Spec.Seed, nil
+		}
+	}
+	return "", fmt.Errorf("datacenter '%s' not found", name)
+}
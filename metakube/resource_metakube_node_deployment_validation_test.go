@@ -0,0 +1,171 @@
+package metakube
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func TestMetakubeNodeDeploymentQuotaExceeded(t *testing.T) {
+	size := &models.OpenstackSize{
+		Slug:   "m1.small",
+		VCPUs:  2,
+		Memory: 4096,
+	}
+	cases := []struct {
+		Limits   *models.Absolute
+		Replicas int64
+		Expected []string
+	}{
+		{
+			Limits:   nil,
+			Replicas: 3,
+			Expected: nil,
+		},
+		{
+			Limits: &models.Absolute{
+				MaxTotalInstances:  10,
+				TotalInstancesUsed: 2,
+				MaxTotalCores:      20,
+				TotalCoresUsed:     4,
+				MaxTotalRAMSize:    40960,
+				TotalRAMUsed:       8192,
+			},
+			Replicas: 3,
+			Expected: nil,
+		},
+		{
+			Limits: &models.Absolute{
+				MaxTotalInstances:  -1,
+				TotalInstancesUsed: 2,
+				MaxTotalCores:      -1,
+				TotalCoresUsed:     4,
+				MaxTotalRAMSize:    -1,
+				TotalRAMUsed:       8192,
+			},
+			Replicas: 100,
+			Expected: nil,
+		},
+		{
+			Limits: &models.Absolute{
+				MaxTotalInstances:  10,
+				TotalInstancesUsed: 9,
+				MaxTotalCores:      8,
+				TotalCoresUsed:     4,
+				MaxTotalRAMSize:    40960,
+				TotalRAMUsed:       8192,
+			},
+			Replicas: 3,
+			Expected: []string{
+				"instances requested 3, available 1",
+				"vCPUs requested 6, available 4",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		output := metakubeNodeDeploymentQuotaExceeded(tc.Limits, size, tc.Replicas)
+		if diff := cmp.Diff(tc.Expected, output); diff != "" {
+			t.Fatalf("Unexpected output from quota check: %s", diff)
+		}
+	}
+}
+
+func TestDynamicConfigDeprecationWarning(t *testing.T) {
+	cases := []struct {
+		Version  string
+		Expected string
+	}{
+		{"1.21.5", ""},
+		{"1.22.2", "kubelet dynamic config is deprecated in kubernetes 1.22.2 and removed in 1.24"},
+		{"1.24.0", "kubelet dynamic config is not supported by kubernetes 1.24.0, dynamic_config has no effect"},
+		{"invalid", ""},
+	}
+
+	for _, tc := range cases {
+		output := dynamicConfigDeprecationWarning(tc.Version)
+		if diff := cmp.Diff(tc.Expected, output); diff != "" {
+			t.Fatalf("Unexpected warning for version %s: %s", tc.Version, diff)
+		}
+	}
+}
+
+func TestMetakubeNodeDeploymentGenerateName(t *testing.T) {
+	valid := regexp.MustCompile(`^pool-[a-z0-9]{5}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		name := metakubeNodeDeploymentGenerateName("pool-")
+		if !valid.MatchString(name) {
+			t.Fatalf("Unexpected generated name %s", name)
+		}
+		seen[name] = true
+	}
+	if len(seen) < 2 {
+		t.Fatalf("Expected generated names to differ, got %v", seen)
+	}
+}
+
+func TestMetakubeNodeDeploymentUniqueName(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Prefix string
+	}{
+		{
+			"short prefix",
+			"pool-",
+		},
+		{
+			"longest prefix",
+			strings.Repeat("a", 50),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			name := metakubeNodeDeploymentUniqueName(tc.Prefix)
+			if len(name) > metakubeNodeDeploymentNameMaxLength {
+				t.Fatalf("Generated name %s exceeds %d characters", name, metakubeNodeDeploymentNameMaxLength)
+			}
+			if prefix := tc.Prefix[:len(name)-resource.UniqueIDSuffixLength]; !strings.HasPrefix(name, prefix) {
+				t.Fatalf("Generated name %s doesn't start with %s", name, prefix)
+			}
+		})
+	}
+}
+
+func TestMetakubeNodeDeploymentPropagatedLabelErrors(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Labels   map[string]interface{}
+		Expected []string
+	}{
+		{
+			"valid labels",
+			map[string]interface{}{"role": "worker", "node.group": "a"},
+			nil,
+		},
+		{
+			"prefixed label",
+			map[string]interface{}{"example.com/role": "worker"},
+			[]string{"invalid metadata key 'example.com/role': must be 255 characters or less and contain only alphanumerics, '-', '_', ':', '.' or ' '"},
+		},
+		{
+			"invalid label value",
+			map[string]interface{}{"role": "a b"},
+			[]string{"invalid label value 'a b': must be 63 characters or less, begin and end with an alphanumeric character and contain only alphanumerics, '-', '_' or '.'"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			output := metakubeNodeDeploymentPropagatedLabelErrors(tc.Labels)
+			if diff := cmp.Diff(tc.Expected, output); diff != "" {
+				t.Fatalf("Unexpected errors: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
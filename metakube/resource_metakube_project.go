@@ -0,0 +1,228 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/project"
+)
+
+const (
+	metakubeProjectStatusActive = "Active"
+)
+
+func metakubeResourceProject() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceProjectCreate,
+		ReadContext:   metakubeResourceProjectRead,
+		UpdateContext: metakubeResourceProjectUpdate,
+		DeleteContext: metakubeResourceProjectDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project name",
+			},
+
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Project labels",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete project even if it still has clusters, which are deleted with it",
+			},
+
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Project status",
+			},
+
+			"creation_timestamp": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creation timestamp",
+			},
+		},
+	}
+}
+
+func metakubeResourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := project.NewCreateProjectParams().
+		WithContext(ctx).
+		WithBody(project.CreateProjectBody{
+			Name:   d.Get("name").(string),
+			Labels: metakubeResourceProjectLabels(d),
+		})
+	r, err := k.client.Project.CreateProject(p, k.auth)
+	if err != nil {
+		return diag.Errorf("unable to create project: %s", stringifyResponseError(err))
+	}
+	d.SetId(r.Payload.ID)
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		prj, err := getProject(k, d.Id())
+		if err != nil {
+			// wait for the RBACs
+			if isForbidden(err) || isNotFound(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(fmt.Errorf("unable to get project '%s': %s", d.Id(), stringifyResponseError(err)))
+		}
+		if prj.Status != metakubeProjectStatusActive {
+			return resource.RetryableError(fmt.Errorf("project '%s' is not active yet, status: %s", d.Id(), prj.Status))
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.Errorf("project is not ready: %v", err)
+	}
+
+	return metakubeResourceProjectRead(ctx, d, m)
+}
+
+func metakubeResourceProjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	prj, err := getProject(k, d.Id())
+	if err != nil {
+		if isNotFound(err) || isForbidden(err) {
+			k.log.Infof("removing project '%s' from terraform state file, could not find the resource", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to get project '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	_ = d.Set("name", prj.Name)
+	_ = d.Set("labels", prj.Labels)
+	_ = d.Set("status", prj.Status)
+	_ = d.Set("creation_timestamp", prj.CreationTimestamp.String())
+
+	return nil
+}
+
+func metakubeResourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	prj, err := getProject(k, d.Id())
+	if err != nil {
+		return diag.Errorf("unable to get project '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	prj.Name = d.Get("name").(string)
+	prj.Labels = metakubeResourceProjectLabels(d)
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		p := project.NewUpdateProjectParams().
+			WithContext(ctx).
+			WithProjectID(d.Id()).
+			WithBody(prj)
+		_, err := k.client.Project.UpdateProject(p, k.auth)
+		if err != nil {
+			if isConflict(err) || isRateLimited(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(fmt.Errorf("unable to update project '%s': %s", d.Id(), stringifyResponseError(err)))
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return metakubeResourceProjectRead(ctx, d, m)
+}
+
+func metakubeResourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+
+	if !d.Get("force_destroy").(bool) {
+		clusters, err := metakubeResourceProjectClusterNames(ctx, k, d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(clusters) > 0 {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Project '%s' still has clusters", d.Id()),
+				Detail:   fmt.Sprintf("Delete clusters %v first or set force_destroy to delete them with the project.", clusters),
+			}}
+		}
+	}
+
+	p := project.NewDeleteProjectParams().
+		WithContext(ctx).
+		WithProjectID(d.Id())
+	if _, err := k.client.Project.DeleteProject(p, k.auth); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.Errorf("unable to delete project '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		prj, err := getProject(k, d.Id())
+		if err != nil {
+			if isNotFound(err) || isForbidden(err) {
+				return nil
+			}
+			return resource.NonRetryableError(fmt.Errorf("unable to get project '%s': %s", d.Id(), stringifyResponseError(err)))
+		}
+		k.log.Debugf("project '%s' deletion in progress, status: %s", d.Id(), prj.Status)
+		return resource.RetryableError(fmt.Errorf("project '%s' deletion in progress", d.Id()))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func metakubeResourceProjectClusterNames(ctx context.Context, k *metakubeProviderMeta, projectID string) ([]string, error) {
+	p := project.NewListClustersV2Params().
+		WithContext(ctx).
+		WithProjectID(projectID)
+	r, err := k.client.Project.ListClustersV2(p, k.auth)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list clusters of project '%s': %s", projectID, stringifyResponseError(err))
+	}
+	var ret []string
+	for _, c := range r.Payload {
+		if c != nil {
+			ret = append(ret, c.Name)
+		}
+	}
+	return ret, nil
+}
+
+func metakubeResourceProjectLabels(d *schema.ResourceData) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range d.Get("labels").(map[string]interface{}) {
+		ret[k] = v.(string)
+	}
+	return ret
+}
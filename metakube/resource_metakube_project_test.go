@@ -0,0 +1,135 @@
+package metakube
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/syseleven/go-metakube/client/project"
+)
+
+func testSweepProjects(region string) error {
+	meta, err := sharedConfigForRegion(region)
+	if err != nil {
+		return err
+	}
+
+	records, err := meta.client.Project.ListProjects(project.NewListProjectsParams(), meta.auth)
+	if err != nil {
+		return fmt.Errorf("list projects: %v", err)
+	}
+
+	for _, rec := range records.Payload {
+		if !strings.HasPrefix(rec.Name, testNamePrefix) || !time.Time(rec.DeletionTimestamp).IsZero() {
+			continue
+		}
+
+		p := project.NewDeleteProjectParams().WithProjectID(rec.ID)
+		if _, err := meta.client.Project.DeleteProject(p, meta.auth); err != nil {
+			return fmt.Errorf("delete project: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func TestAccMetakubeProject_Basic(t *testing.T) {
+	testName := makeRandomName()
+	updatedName := makeRandomName()
+	resourceName := "metakube_project.acctest_project"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetaKubeProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckMetaKubeProjectConfigBasic, testName, "a"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeProjectExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", testName),
+					resource.TestCheckResourceAttr(resourceName, "labels.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "labels.foo", "a"),
+					resource.TestCheckResourceAttr(resourceName, "status", metakubeProjectStatusActive),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckMetaKubeProjectConfigBasic, updatedName, "b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeProjectExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", updatedName),
+					resource.TestCheckResourceAttr(resourceName, "labels.foo", "b"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force_destroy"},
+			},
+			// Test importing non-existent resource provides expected error.
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: false,
+				ImportStateId:     "123abc",
+				ExpectError:       regexp.MustCompile(`(Please verify the ID is correct|Cannot import non-existent remote object)`),
+			},
+		},
+	})
+}
+
+const testAccCheckMetaKubeProjectConfigBasic = `
+resource "metakube_project" "acctest_project" {
+	name = "%s"
+
+	labels = {
+		foo = "%s"
+	}
+}
+`
+
+func testAccCheckMetaKubeProjectDestroy(s *terraform.State) error {
+	k := testAccProvider.Meta().(*metakubeProviderMeta)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metakube_project" {
+			continue
+		}
+
+		_, err := getProject(k, rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Project still exists")
+		}
+		// API returns 403 if project doesn't exist.
+		if !isNotFound(err) && !isForbidden(err) {
+			return fmt.Errorf("check destroy: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckMetaKubeProjectExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Record ID is set")
+		}
+
+		k := testAccProvider.Meta().(*metakubeProviderMeta)
+		if _, err := getProject(k, rs.Primary.ID); err != nil {
+			return fmt.Errorf("Cannot verify record exist, get project error: %v", err)
+		}
+
+		return nil
+	}
+}
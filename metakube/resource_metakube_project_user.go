@@ -0,0 +1,193 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/users"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func metakubeResourceProjectUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceProjectUserCreate,
+		ReadContext:   metakubeResourceProjectUserRead,
+		UpdateContext: metakubeResourceProjectUserUpdate,
+		DeleteContext: metakubeResourceProjectUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: metakubeResourceProjectUserImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project the user is member of",
+			},
+
+			"email": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				DiffSuppressFunc: func(_, old, new string, _ *schema.ResourceData) bool {
+					return strings.EqualFold(old, new)
+				},
+				Description: "Email of the user",
+			},
+
+			"group": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"owners", "editors", "viewers"}, false),
+				Description:  "Role of the user in the project, one of owners, editors or viewers",
+			},
+
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the user",
+			},
+		},
+	}
+}
+
+func metakubeResourceProjectUserImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("please provide resource identifier in format 'project_id:email'")
+	}
+	d.Set("project_id", parts[0])
+	d.Set("email", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+func metakubeResourceProjectUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	p := users.NewAddUserToProjectParams().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithBody(&models.User{
+			Email: d.Get("email").(string),
+			Projects: []*models.ProjectGroup{
+				{
+					ID:          projectID,
+					GroupPrefix: d.Get("group").(string),
+				},
+			},
+		})
+	r, err := k.client.Users.AddUserToProject(p, k.auth)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("unable to add user '%s' to project: %s", d.Get("email").(string), stringifyResponseError(err)),
+			Detail:   "Users have to log in to MetaKube at least once before they can be added to a project.",
+		}}
+	}
+	d.SetId(r.Payload.ID)
+
+	return metakubeResourceProjectUserRead(ctx, d, m)
+}
+
+func metakubeResourceProjectUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	email := d.Get("email").(string)
+	user, err := metakubeResourceProjectUserFindByEmail(ctx, k, projectID, email)
+	if err != nil {
+		if isNotFound(err) || isForbidden(err) {
+			k.log.Infof("removing project user '%s' from terraform state file, could not find the project", email)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to list users of project '%s': %s", projectID, stringifyResponseError(err))
+	}
+	if user == nil {
+		k.log.Infof("removing project user '%s' from terraform state file, user is not member of the project", email)
+		d.SetId("")
+		return nil
+	}
+
+	d.SetId(user.ID)
+	_ = d.Set("email", user.Email)
+	_ = d.Set("name", user.Name)
+	for _, g := range user.Projects {
+		if g != nil && g.ID == projectID {
+			_ = d.Set("group", metakubeProjectUserGroup(g.GroupPrefix))
+		}
+	}
+
+	return nil
+}
+
+func metakubeResourceProjectUserFindByEmail(ctx context.Context, k *metakubeProviderMeta, projectID, email string) (*models.User, error) {
+	p := users.NewGetUsersForProjectParams().
+		WithContext(ctx).
+		WithProjectID(projectID)
+	r, err := k.client.Users.GetUsersForProject(p, k.auth)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range r.Payload {
+		if u != nil && strings.EqualFold(u.Email, email) {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+// metakubeProjectUserGroup strips project id suffix from group, e.g. "editors-abcd1234" is returned as "editors".
+func metakubeProjectUserGroup(group string) string {
+	for _, g := range []string{"owners", "editors", "viewers"} {
+		if strings.HasPrefix(group, g) {
+			return g
+		}
+	}
+	return group
+}
+
+func metakubeResourceProjectUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	p := users.NewEditUserInProjectParams().
+		WithContext(ctx).
+		WithProjectID(projectID).
+		WithUserID(d.Id()).
+		WithBody(&models.User{
+			ID:    d.Id(),
+			Email: d.Get("email").(string),
+			Projects: []*models.ProjectGroup{
+				{
+					ID:          projectID,
+					GroupPrefix: d.Get("group").(string),
+				},
+			},
+		})
+	if _, err := k.client.Users.EditUserInProject(p, k.auth); err != nil {
+		return diag.Errorf("unable to change group of user '%s': %s", d.Get("email").(string), stringifyResponseError(err))
+	}
+
+	return metakubeResourceProjectUserRead(ctx, d, m)
+}
+
+func metakubeResourceProjectUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := users.NewDeleteUserFromProjectParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithUserID(d.Id())
+	if _, err := k.client.Users.DeleteUserFromProject(p, k.auth); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.Errorf("unable to remove user '%s' from project: %s", d.Get("email").(string), stringifyResponseError(err))
+	}
+	return nil
+}
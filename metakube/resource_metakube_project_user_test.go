@@ -0,0 +1,100 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccMetakubeProjectUser_Basic(t *testing.T) {
+	projectName := makeRandomName()
+	email := os.Getenv(testEnvOtherUserEmail)
+	resourceName := "metakube_project_user.acctest_user"
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			checkEnv(t, testEnvOtherUserEmail)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetaKubeProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckMetaKubeProjectUserConfigBasic, projectName, email, "editors"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeProjectUserGroup(resourceName, "editors"),
+					resource.TestCheckResourceAttr(resourceName, "email", email),
+					resource.TestCheckResourceAttr(resourceName, "group", "editors"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckMetaKubeProjectUserConfigBasic, projectName, email, "viewers"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeProjectUserGroup(resourceName, "viewers"),
+					resource.TestCheckResourceAttr(resourceName, "group", "viewers"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("not found")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project_id"], rs.Primary.Attributes["email"]), nil
+				},
+			},
+			// Test importing non-existent resource provides expected error.
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: false,
+				ImportStateId:     "123abc",
+				ExpectError:       regexp.MustCompile(`please provide resource identifier in format 'project_id:email'`),
+			},
+		},
+	})
+}
+
+const testAccCheckMetaKubeProjectUserConfigBasic = `
+resource "metakube_project" "acctest_project" {
+	name = "%s"
+}
+
+resource "metakube_project_user" "acctest_user" {
+	project_id = metakube_project.acctest_project.id
+	email = "%s"
+	group = "%s"
+}
+`
+
+func testAccCheckMetaKubeProjectUserGroup(n, group string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		k := testAccProvider.Meta().(*metakubeProviderMeta)
+		projectID := rs.Primary.Attributes["project_id"]
+		user, err := metakubeResourceProjectUserFindByEmail(context.Background(), k, projectID, rs.Primary.Attributes["email"])
+		if err != nil {
+			return fmt.Errorf("Cannot verify record exist, list project users error: %v", err)
+		}
+		if user == nil {
+			return fmt.Errorf("Record not found")
+		}
+		for _, g := range user.Projects {
+			if g.ID == projectID && metakubeProjectUserGroup(g.GroupPrefix) == group {
+				return nil
+			}
+		}
+		return fmt.Errorf("want user in group %s, got %v", group, user.Projects)
+	}
+}
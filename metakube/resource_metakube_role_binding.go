@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/syseleven/go-metakube/client/project"
+	"github.com/syseleven/go-metakube/models"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -16,6 +18,7 @@ func metakubeResourceRoleBinding() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: metakubeResourceRoleBindingCreate,
 		ReadContext:   metakubeResourceRoleBindingRead,
+		UpdateContext: metakubeResourceRoleBindingUpdate,
 		DeleteContext: metakubeResourceRoleBindingDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
@@ -62,7 +65,6 @@ func metakubeResourceRoleBinding() *schema.Resource {
 			"subject": {
 				Type:        schema.TypeList,
 				Required:    true,
-				ForceNew:    true,
 				Description: "Users and groups to bind for",
 				MinItems:    1,
 				Elem: &schema.Resource{
@@ -89,7 +91,72 @@ func metakubeResourceRoleBinding() *schema.Resource {
 func metakubeResourceRoleBindingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	k := m.(*metakubeProviderMeta)
 
+	if diags := metakubeResourceRoleBindingValidateRole(ctx, d, k); diags.HasError() {
+		return diags
+	}
+
 	subjects := metakubeRoleBindingExpandSubjects(d.Get("subject"))
+	if err := metakubeResourceRoleBindingBindSubjects(ctx, d, k, subjects); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create role bindings: %v", err))
+	}
+	d.SetId(d.Get("namespace").(string) + ":" + d.Get("role_name").(string))
+	return metakubeResourceRoleBindingRead(ctx, d, m)
+}
+
+func metakubeResourceRoleBindingValidateRole(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta) diag.Diagnostics {
+	namespace := d.Get("namespace").(string)
+	roleName := d.Get("role_name").(string)
+	params := project.NewListRoleNamesV2Params().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithClusterID(d.Get("cluster_id").(string))
+	ret, err := k.client.Project.ListRoleNamesV2(params, k.auth)
+	if err != nil {
+		return diag.Errorf("failed to list roles: %s", stringifyResponseError(err))
+	}
+
+	var available []string
+	for _, role := range ret.Payload {
+		if role == nil {
+			continue
+		}
+		for _, ns := range role.Namespace {
+			if ns != namespace {
+				continue
+			}
+			if role.Name == roleName {
+				return nil
+			}
+			available = append(available, role.Name)
+		}
+	}
+
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       fmt.Sprintf("role '%s' not found in namespace '%s'", roleName, namespace),
+		Detail:        fmt.Sprintf("Available roles: %s", strings.Join(available, ", ")),
+		AttributePath: cty.GetAttrPath("role_name"),
+	}}
+}
+
+func metakubeResourceRoleBindingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+
+	if d.HasChange("subject") {
+		o, n := d.GetChange("subject")
+		oldSubjects := metakubeRoleBindingExpandSubjects(o)
+		newSubjects := metakubeRoleBindingExpandSubjects(n)
+		if err := metakubeResourceRoleBindingBindSubjects(ctx, d, k, metakubeRoleBindingSubjectsDifference(newSubjects, oldSubjects)); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to update role binding: %v", err))
+		}
+		if err := metakubeResourceRoleBindingUnbindSubjects(ctx, d, k, metakubeRoleBindingSubjectsDifference(oldSubjects, newSubjects)); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to update role binding: %v", err))
+		}
+	}
+	return metakubeResourceRoleBindingRead(ctx, d, m)
+}
+
+func metakubeResourceRoleBindingBindSubjects(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta, subjects []models.RoleUser) error {
 	for _, sub := range subjects {
 		params := project.NewBindUserToRoleV2Params().
 			WithContext(ctx).
@@ -100,11 +167,30 @@ func metakubeResourceRoleBindingCreate(ctx context.Context, d *schema.ResourceDa
 			WithBody(&sub)
 		_, err := k.client.Project.BindUserToRoleV2(params, k.auth)
 		if err != nil {
-			return diag.FromErr(fmt.Errorf("failed to create role bindings: %s", stringifyResponseError(err)))
+			return fmt.Errorf("%s", stringifyResponseError(err))
 		}
 	}
-	d.SetId(d.Get("namespace").(string) + ":" + d.Get("role_name").(string))
-	return metakubeResourceRoleBindingRead(ctx, d, m)
+	return nil
+}
+
+func metakubeResourceRoleBindingUnbindSubjects(ctx context.Context, d *schema.ResourceData, k *metakubeProviderMeta, subjects []models.RoleUser) error {
+	idParts := strings.Split(d.Id(), ":")
+	namespace := idParts[0]
+	roleName := idParts[1]
+	for _, sub := range subjects {
+		params := project.NewUnbindUserFromRoleBindingV2Params().
+			WithContext(ctx).
+			WithProjectID(d.Get("project_id").(string)).
+			WithClusterID(d.Get("cluster_id").(string)).
+			WithNamespace(namespace).
+			WithRoleID(roleName).
+			WithBody(&sub)
+		_, err := k.client.Project.UnbindUserFromRoleBindingV2(params, k.auth)
+		if err != nil {
+			return fmt.Errorf("%s", stringifyResponseError(err))
+		}
+	}
+	return nil
 }
 
 func metakubeResourceRoleBindingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -122,27 +208,22 @@ func metakubeResourceRoleBindingRead(ctx context.Context, d *schema.ResourceData
 	idParts := strings.Split(d.Id(), ":")
 	namespace := idParts[0]
 	roleName := idParts[1]
-	for _, item := range ret.Payload {
-		if item.Namespace == namespace && item.RoleRefName == roleName && len(item.Subjects) != 0 {
-			err := d.Set("subject", metakubeClusterRoleBindingFlattenSubjects(item.Subjects))
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			d.SetId(item.Namespace + ":" + item.RoleRefName)
-			err = d.Set("namespace", item.Namespace)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			err = d.Set("role_name", item.RoleRefName)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			return nil
-		}
+	subjects := metakubeRoleBindingFlattenSubjects(ret.Payload, namespace, roleName, d.Get("subject").([]interface{}))
+	if len(subjects) == 0 {
+		// Signal record was not found by setting id = ""
+		d.SetId("")
+		return nil
 	}
 
-	// Signal record was not found by setting id = ""
-	d.SetId("")
+	if err := d.Set("subject", subjects); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("namespace", namespace); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("role_name", roleName); err != nil {
+		return diag.FromErr(err)
+	}
 	return nil
 }
 
@@ -150,21 +231,8 @@ func metakubeResourceRoleBindingDelete(ctx context.Context, d *schema.ResourceDa
 	k := m.(*metakubeProviderMeta)
 
 	subjects := metakubeRoleBindingExpandSubjects(d.Get("subject"))
-	idParts := strings.Split(d.Id(), ":")
-	namespace := idParts[0]
-	roleName := idParts[1]
-	for _, sub := range subjects {
-		params := project.NewUnbindUserFromRoleBindingV2Params().
-			WithContext(ctx).
-			WithProjectID(d.Get("project_id").(string)).
-			WithClusterID(d.Get("cluster_id").(string)).
-			WithNamespace(namespace).
-			WithRoleID(roleName).
-			WithBody(&sub)
-		_, err := k.client.Project.UnbindUserFromRoleBindingV2(params, k.auth)
-		if err != nil {
-			return diag.FromErr(fmt.Errorf("failed to delete role binding: %s", stringifyResponseError(err)))
-		}
+	if err := metakubeResourceRoleBindingUnbindSubjects(ctx, d, k, subjects); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to delete role binding: %v", err))
 	}
 	return nil
 }
@@ -1,6 +1,8 @@
 package metakube
 
 import (
+	"strings"
+
 	"github.com/syseleven/go-metakube/models"
 )
 
@@ -28,3 +30,62 @@ func metakubeRoleBindingExpandSubjects(p interface{}) []models.RoleUser {
 	}
 	return result
 }
+
+// metakubeRoleBindingSubjectsDifference returns subjects of a that are not in b.
+func metakubeRoleBindingSubjectsDifference(a, b []models.RoleUser) []models.RoleUser {
+	var result []models.RoleUser
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if x.UserEmail == y.UserEmail && x.Group == y.Group {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// metakubeRoleBindingFlattenSubjects returns subjects of all bindings to the role in the namespace.
+// If current subjects are given, only those are returned, in the same order, so that subjects
+// bound to the same role by other bindings are not taken over by the resource.
+func metakubeRoleBindingFlattenSubjects(bindings []*models.RoleBinding, namespace, roleName string, current []interface{}) []interface{} {
+	var all []interface{}
+	bound := make(map[string]bool)
+	for _, item := range bindings {
+		if item == nil || item.Namespace != namespace || item.RoleRefName != roleName {
+			continue
+		}
+		for _, s := range metakubeClusterRoleBindingFlattenSubjects(item.Subjects) {
+			m := s.(map[string]interface{})
+			key := m["kind"].(string) + ":" + m["name"].(string)
+			if !bound[key] {
+				bound[key] = true
+				all = append(all, s)
+			}
+		}
+	}
+	if len(current) == 0 {
+		return all
+	}
+
+	var result []interface{}
+	for _, s := range current {
+		m, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := m["kind"].(string)
+		name, _ := m["name"].(string)
+		if bound[strings.ToLower(kind)+":"+name] {
+			result = append(result, map[string]interface{}{
+				"kind": kind,
+				"name": name,
+			})
+		}
+	}
+	return result
+}
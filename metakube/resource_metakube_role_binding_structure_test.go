@@ -0,0 +1,82 @@
+package metakube
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func TestMetakubeRoleBindingFlattenSubjects(t *testing.T) {
+	bindings := []*models.RoleBinding{
+		{
+			Namespace:   "default",
+			RoleRefName: "namespace-viewer",
+			Subjects: []*models.Subject{
+				{Kind: "User", Name: "foo@example.com"},
+				{Kind: "Group", Name: "ops"},
+			},
+		},
+		{
+			Namespace:   "default",
+			RoleRefName: "namespace-viewer",
+			Subjects: []*models.Subject{
+				{Kind: "User", Name: "bar@example.com"},
+				{Kind: "Group", Name: "ops"},
+			},
+		},
+		{
+			Namespace:   "kube-system",
+			RoleRefName: "namespace-viewer",
+			Subjects: []*models.Subject{
+				{Kind: "User", Name: "baz@example.com"},
+			},
+		},
+	}
+
+	cases := []struct {
+		Name           string
+		Current        []interface{}
+		ExpectedOutput []interface{}
+	}{
+		{
+			"import takes all subjects of the role",
+			nil,
+			[]interface{}{
+				map[string]interface{}{"kind": "user", "name": "foo@example.com"},
+				map[string]interface{}{"kind": "group", "name": "ops"},
+				map[string]interface{}{"kind": "user", "name": "bar@example.com"},
+			},
+		},
+		{
+			"subjects of other bindings are ignored",
+			[]interface{}{
+				map[string]interface{}{"kind": "group", "name": "ops"},
+				map[string]interface{}{"kind": "user", "name": "bar@example.com"},
+			},
+			[]interface{}{
+				map[string]interface{}{"kind": "group", "name": "ops"},
+				map[string]interface{}{"kind": "user", "name": "bar@example.com"},
+			},
+		},
+		{
+			"subjects removed out of band are dropped",
+			[]interface{}{
+				map[string]interface{}{"kind": "user", "name": "baz@example.com"},
+				map[string]interface{}{"kind": "user", "name": "foo@example.com"},
+			},
+			[]interface{}{
+				map[string]interface{}{"kind": "user", "name": "foo@example.com"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			output := metakubeRoleBindingFlattenSubjects(bindings, "default", "namespace-viewer", tc.Current)
+			if diff := cmp.Diff(tc.ExpectedOutput, output); diff != "" {
+				t.Fatalf("Unexpected output from flattener: mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
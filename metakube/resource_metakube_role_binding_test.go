@@ -15,7 +15,7 @@ import (
 func TestAccMetakubeRoleBinding(t *testing.T) {
 	resourceName := "metakube_role_binding.acctest"
 	params := &testAccCheckMetaKubeRoleBindingBasicParams{
-		ClusterName:                          randomName("testacc", 5),
+		ClusterName:                          makeRandomName(),
 		DatacenterName:                       os.Getenv(testEnvOpenstackNodeDC),
 		ProjectID:                            os.Getenv(testEnvProjectID),
 		Version:                              os.Getenv(testEnvK8sVersion),
@@ -27,6 +27,8 @@ func TestAccMetakubeRoleBinding(t *testing.T) {
 		UserSubjectName:  "foo.bar@mycompany.xyz",
 		GroupSubjectName: "support-team",
 	}
+	updatedParams := *params
+	updatedParams.GroupSubjectName = "ops-team"
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
@@ -44,6 +46,14 @@ func TestAccMetakubeRoleBinding(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "subject.1.name", params.GroupSubjectName),
 				),
 			},
+			{
+				Config: testAccCheckMetaKubeRoleBindingBasicConfig(t, &updatedParams),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "subject.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "subject.0.name", params.UserSubjectName),
+					resource.TestCheckResourceAttr(resourceName, "subject.1.name", updatedParams.GroupSubjectName),
+				),
+			},
 			{
 				ResourceName:      resourceName,
 				ImportState:       true,
@@ -0,0 +1,157 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/serviceaccounts"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func metakubeResourceServiceAccount() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceServiceAccountCreate,
+		ReadContext:   metakubeResourceServiceAccountRead,
+		UpdateContext: metakubeResourceServiceAccountUpdate,
+		DeleteContext: metakubeResourceServiceAccountDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: metakubeResourceServiceAccountImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project the service account belongs to",
+			},
+
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Service account name",
+			},
+
+			"group": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"editors", "viewers"}, false),
+				Description:  "Role of the service account in the project, one of editors or viewers",
+			},
+
+			"creation_timestamp": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creation timestamp",
+			},
+		},
+	}
+}
+
+func metakubeResourceServiceAccountImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("please provide resource identifier in format 'project_id:service_account_id'")
+	}
+	d.Set("project_id", parts[0])
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+func metakubeResourceServiceAccountCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := serviceaccounts.NewAddServiceAccountToProjectParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithBody(&models.ServiceAccount{
+			Name:  d.Get("name").(string),
+			Group: d.Get("group").(string),
+		})
+	r, err := k.client.Serviceaccounts.AddServiceAccountToProject(p, k.auth)
+	if err != nil {
+		return diag.Errorf("unable to create service account: %s", stringifyResponseError(err))
+	}
+	d.SetId(r.Payload.ID)
+
+	return metakubeResourceServiceAccountRead(ctx, d, m)
+}
+
+func metakubeResourceServiceAccountRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	projectID := d.Get("project_id").(string)
+	sa, err := metakubeResourceServiceAccountFind(ctx, k, projectID, d.Id())
+	if err != nil {
+		if isNotFound(err) || isForbidden(err) {
+			k.log.Infof("removing service account '%s' from terraform state file, could not find the project", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to list service accounts of project '%s': %s", projectID, stringifyResponseError(err))
+	}
+	if sa == nil {
+		k.log.Infof("removing service account '%s' from terraform state file, could not find the resource", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("name", sa.Name)
+	_ = d.Set("group", metakubeProjectUserGroup(sa.Group))
+	_ = d.Set("creation_timestamp", sa.CreationTimestamp.String())
+
+	return nil
+}
+
+func metakubeResourceServiceAccountFind(ctx context.Context, k *metakubeProviderMeta, projectID, id string) (*models.ServiceAccount, error) {
+	p := serviceaccounts.NewListServiceAccountsParams().
+		WithContext(ctx).
+		WithProjectID(projectID)
+	r, err := k.client.Serviceaccounts.ListServiceAccounts(p, k.auth)
+	if err != nil {
+		return nil, err
+	}
+	for _, sa := range r.Payload {
+		if sa != nil && sa.ID == id {
+			return sa, nil
+		}
+	}
+	return nil, nil
+}
+
+func metakubeResourceServiceAccountUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := serviceaccounts.NewUpdateServiceAccountParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithServiceAccountID(d.Id()).
+		WithBody(&models.ServiceAccount{
+			ID:    d.Id(),
+			Name:  d.Get("name").(string),
+			Group: d.Get("group").(string),
+		})
+	if _, err := k.client.Serviceaccounts.UpdateServiceAccount(p, k.auth); err != nil {
+		return diag.Errorf("unable to update service account '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+
+	return metakubeResourceServiceAccountRead(ctx, d, m)
+}
+
+func metakubeResourceServiceAccountDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := serviceaccounts.NewDeleteServiceAccountParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithServiceAccountID(d.Id())
+	if _, err := k.client.Serviceaccounts.DeleteServiceAccount(p, k.auth); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.Errorf("unable to delete service account '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccMetakubeServiceAccount_Basic(t *testing.T) {
+	projectName := makeRandomName()
+	name := makeRandomName()
+	updatedName := makeRandomName()
+	resourceName := "metakube_service_account.acctest_sa"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetaKubeProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckMetaKubeServiceAccountConfigBasic, projectName, name, "editors"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeServiceAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "group", "editors"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckMetaKubeServiceAccountConfigBasic, projectName, updatedName, "viewers"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeServiceAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", updatedName),
+					resource.TestCheckResourceAttr(resourceName, "group", "viewers"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("not found")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project_id"], rs.Primary.ID), nil
+				},
+			},
+			// Test importing non-existent resource provides expected error.
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: false,
+				ImportStateId:     "123abc",
+				ExpectError:       regexp.MustCompile(`please provide resource identifier in format 'project_id:service_account_id'`),
+			},
+		},
+	})
+}
+
+const testAccCheckMetaKubeServiceAccountConfigBasic = `
+resource "metakube_project" "acctest_project" {
+	name = "%s"
+}
+
+resource "metakube_service_account" "acctest_sa" {
+	project_id = metakube_project.acctest_project.id
+	name = "%s"
+	group = "%s"
+}
+`
+
+func testAccCheckMetaKubeServiceAccountExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Record ID is set")
+		}
+
+		k := testAccProvider.Meta().(*metakubeProviderMeta)
+		sa, err := metakubeResourceServiceAccountFind(context.Background(), k, rs.Primary.Attributes["project_id"], rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Cannot verify record exist, list service accounts error: %v", err)
+		}
+		if sa == nil {
+			return fmt.Errorf("Record not found")
+		}
+
+		return nil
+	}
+}
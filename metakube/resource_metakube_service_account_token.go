@@ -0,0 +1,186 @@
+package metakube
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/syseleven/go-metakube/client/tokens"
+	"github.com/syseleven/go-metakube/models"
+)
+
+func metakubeResourceServiceAccountToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: metakubeResourceServiceAccountTokenCreate,
+		ReadContext:   metakubeResourceServiceAccountTokenRead,
+		UpdateContext: metakubeResourceServiceAccountTokenUpdate,
+		DeleteContext: metakubeResourceServiceAccountTokenDelete,
+		CustomizeDiff: metakubeResourceServiceAccountTokenCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Project the service account belongs to",
+			},
+
+			"service_account_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Service account the token belongs to",
+			},
+
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Token name",
+			},
+
+			"rotate_before": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: isNonEmptyDurationString,
+				Description:      "Replace the token when it expires within this duration, e.g. 720h",
+			},
+
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Token value, only available when the token is created",
+			},
+
+			"expiry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiry timestamp of the token",
+			},
+		},
+	}
+}
+
+func metakubeResourceServiceAccountTokenCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	expiry, err := time.Parse(time.RFC3339, d.Get("expiry").(string))
+	if err != nil {
+		return nil
+	}
+	rotateBefore, err := time.ParseDuration(d.Get("rotate_before").(string))
+	if err != nil {
+		return nil
+	}
+	if !metakubeServiceAccountTokenNeedsRotation(expiry, rotateBefore, time.Now()) {
+		return nil
+	}
+	if err := d.SetNewComputed("token"); err != nil {
+		return err
+	}
+	if err := d.SetNewComputed("expiry"); err != nil {
+		return err
+	}
+	return d.ForceNew("expiry")
+}
+
+// metakubeServiceAccountTokenNeedsRotation returns true if token expires within rotateBefore from now.
+func metakubeServiceAccountTokenNeedsRotation(expiry time.Time, rotateBefore time.Duration, now time.Time) bool {
+	return rotateBefore > 0 && !now.Add(rotateBefore).Before(expiry)
+}
+
+func metakubeResourceServiceAccountTokenCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := tokens.NewAddTokenToServiceAccountParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithServiceAccountID(d.Get("service_account_id").(string)).
+		WithBody(&models.ServiceAccountToken{
+			Name: d.Get("name").(string),
+		})
+	r, err := k.client.Tokens.AddTokenToServiceAccount(p, k.auth)
+	if err != nil {
+		return diag.Errorf("unable to create service account token: %s", stringifyResponseError(err))
+	}
+	d.SetId(r.Payload.ID)
+	// Token value is only returned on creation.
+	_ = d.Set("token", r.Payload.Token)
+
+	return metakubeResourceServiceAccountTokenRead(ctx, d, m)
+}
+
+func metakubeResourceServiceAccountTokenRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	serviceAccountID := d.Get("service_account_id").(string)
+	p := tokens.NewListServiceAccountTokensParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithServiceAccountID(serviceAccountID)
+	r, err := k.client.Tokens.ListServiceAccountTokens(p, k.auth)
+	if err != nil {
+		if isNotFound(err) || isForbidden(err) {
+			k.log.Infof("removing service account token '%s' from terraform state file, could not find the service account", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("unable to list tokens of service account '%s': %s", serviceAccountID, stringifyResponseError(err))
+	}
+
+	var token *models.PublicServiceAccountToken
+	for _, t := range r.Payload {
+		if t != nil && t.ID == d.Id() {
+			token = t
+		}
+	}
+	if token == nil {
+		k.log.Infof("removing service account token '%s' from terraform state file, could not find the resource", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("name", token.Name)
+	_ = d.Set("expiry", time.Time(token.Expiry).Format(time.RFC3339))
+
+	return nil
+}
+
+func metakubeResourceServiceAccountTokenUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	if d.HasChange("name") {
+		p := tokens.NewPatchServiceAccountTokenParams().
+			WithContext(ctx).
+			WithProjectID(d.Get("project_id").(string)).
+			WithServiceAccountID(d.Get("service_account_id").(string)).
+			WithTokenID(d.Id()).
+			WithBody(&models.PublicServiceAccountToken{
+				Name: d.Get("name").(string),
+			})
+		if _, err := k.client.Tokens.PatchServiceAccountToken(p, k.auth); err != nil {
+			return diag.Errorf("unable to rename service account token '%s': %s", d.Id(), stringifyResponseError(err))
+		}
+	}
+
+	return metakubeResourceServiceAccountTokenRead(ctx, d, m)
+}
+
+func metakubeResourceServiceAccountTokenDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	k := m.(*metakubeProviderMeta)
+	p := tokens.NewDeleteServiceAccountTokenParams().
+		WithContext(ctx).
+		WithProjectID(d.Get("project_id").(string)).
+		WithServiceAccountID(d.Get("service_account_id").(string)).
+		WithTokenID(d.Id())
+	if _, err := k.client.Tokens.DeleteServiceAccountToken(p, k.auth); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.Errorf("unable to revoke service account token '%s': %s", d.Id(), stringifyResponseError(err))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package metakube
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/syseleven/go-metakube/client/tokens"
+)
+
+func TestMetakubeServiceAccountTokenRotation(t *testing.T) {
+	cases := []struct {
+		Name            string
+		Expiry          time.Time
+		RotateBefore    string
+		ExpectedReplace bool
+	}{
+		{
+			"no rotate_before",
+			time.Now().Add(time.Hour),
+			"",
+			false,
+		},
+		{
+			"expiry outside window",
+			time.Now().Add(48 * time.Hour),
+			"24h",
+			false,
+		},
+		{
+			"expiry within window",
+			time.Now().Add(time.Hour),
+			"24h",
+			true,
+		},
+		{
+			"expired",
+			time.Now().Add(-time.Hour),
+			"1h",
+			true,
+		},
+	}
+
+	r := metakubeResourceServiceAccountToken()
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			state := &terraform.InstanceState{
+				ID: "token-id",
+				Attributes: map[string]string{
+					"id":                 "token-id",
+					"project_id":         "project-id",
+					"service_account_id": "sa-id",
+					"name":               "ci",
+					"rotate_before":      tc.RotateBefore,
+					"token":              "secret",
+					"expiry":             tc.Expiry.Format(time.RFC3339),
+				},
+			}
+			raw := map[string]interface{}{
+				"project_id":         "project-id",
+				"service_account_id": "sa-id",
+				"name":               "ci",
+			}
+			if tc.RotateBefore != "" {
+				raw["rotate_before"] = tc.RotateBefore
+			}
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if replace := diff != nil && diff.RequiresNew(); replace != tc.ExpectedReplace {
+				t.Fatalf("want replace %v, got %v", tc.ExpectedReplace, replace)
+			}
+		})
+	}
+}
+
+func TestAccMetakubeServiceAccountToken_Basic(t *testing.T) {
+	projectName := makeRandomName()
+	saName := makeRandomName()
+	name := makeRandomName()
+	updatedName := makeRandomName()
+	resourceName := "metakube_service_account_token.acctest_token"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMetaKubeProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckMetaKubeServiceAccountTokenConfigBasic, projectName, saName, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeServiceAccountTokenExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttrSet(resourceName, "token"),
+					resource.TestCheckResourceAttrSet(resourceName, "expiry"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckMetaKubeServiceAccountTokenConfigBasic, projectName, saName, updatedName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckMetaKubeServiceAccountTokenExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", updatedName),
+					resource.TestCheckResourceAttrSet(resourceName, "token"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckMetaKubeServiceAccountTokenConfigBasic = `
+resource "metakube_project" "acctest_project" {
+	name = "%s"
+}
+
+resource "metakube_service_account" "acctest_sa" {
+	project_id = metakube_project.acctest_project.id
+	name = "%s"
+	group = "viewers"
+}
+
+resource "metakube_service_account_token" "acctest_token" {
+	project_id = metakube_project.acctest_project.id
+	service_account_id = metakube_service_account.acctest_sa.id
+	name = "%s"
+	rotate_before = "24h"
+}
+`
+
+func testAccCheckMetaKubeServiceAccountTokenExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Record ID is set")
+		}
+
+		k := testAccProvider.Meta().(*metakubeProviderMeta)
+		p := tokens.NewListServiceAccountTokensParams().
+			WithProjectID(rs.Primary.Attributes["project_id"]).
+			WithServiceAccountID(rs.Primary.Attributes["service_account_id"])
+		r, err := k.client.Tokens.ListServiceAccountTokens(p, k.auth)
+		if err != nil {
+			return fmt.Errorf("Cannot verify record exist, list tokens error: %v", err)
+		}
+		for _, t := range r.Payload {
+			if t != nil && t.ID == rs.Primary.ID {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Record not found")
+	}
+}
@@ -2,6 +2,8 @@ package metakube
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"strings"
 
@@ -20,7 +22,7 @@ func metakubeResourceSSHKey() *schema.Resource {
 		ReadContext:   metakubeResourceSSHKeyRead,
 		DeleteContext: metakubeResourceSSHKeyDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: metakubeResourceSSHKeyImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -41,16 +43,65 @@ func metakubeResourceSSHKey() *schema.Resource {
 			"public_key": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ValidateFunc: validation.NoZeroValues,
+				ValidateFunc: validateSSHPublicKey,
 				DiffSuppressFunc: func(_, old, new string, _ *schema.ResourceData) bool {
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
 				ForceNew: true,
 			},
+
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Fingerprint of the public key",
+			},
 		},
 	}
 }
 
+func metakubeResourceSSHKeyImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	// Keys imported by id only are looked up in all projects on read.
+	if parts := strings.Split(d.Id(), ":"); len(parts) == 2 {
+		d.Set("project_id", parts[0])
+		d.SetId(parts[1])
+	} else if len(parts) > 2 {
+		return nil, fmt.Errorf("please provide resource identifier in format 'project_id:key_id'")
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+var sshPublicKeyTypes = map[string]bool{
+	"ssh-rsa":             true,
+	"ssh-ed25519":         true,
+	"ecdsa-sha2-nistp256": true,
+	"ecdsa-sha2-nistp384": true,
+	"ecdsa-sha2-nistp521": true,
+}
+
+// validateSSHPublicKey checks public key is in authorized_keys format and key data matches key type.
+func validateSSHPublicKey(v interface{}, k string) ([]string, []error) {
+	fields := strings.Fields(v.(string))
+	if len(fields) < 2 {
+		return nil, []error{fmt.Errorf("%s must be in format '<type> <base64 key> [comment]'", k)}
+	}
+	if !sshPublicKeyTypes[fields[0]] {
+		return nil, []error{fmt.Errorf("%s has unsupported key type '%s', expected ssh-rsa, ssh-ed25519 or ecdsa-sha2-*", k, fields[0])}
+	}
+	data, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s key data is not valid base64: %v", k, err)}
+	}
+	// Key data starts with key type prefixed by its length.
+	if len(data) < 4 {
+		return nil, []error{fmt.Errorf("%s key data is too short", k)}
+	}
+	n := binary.BigEndian.Uint32(data[:4])
+	if uint64(len(data)) < 4+uint64(n) || string(data[4:4+n]) != fields[0] {
+		return nil, []error{fmt.Errorf("%s key data does not match key type '%s'", k, fields[0])}
+	}
+	return nil, nil
+}
+
 func metakubeResourceSSHKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	k := m.(*metakubeProviderMeta)
 	p := project.NewCreateSSHKeyParams()
@@ -81,6 +132,7 @@ func metakubeResourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m i
 	}
 	_ = d.Set("name", sshkey.Name)
 	_ = d.Set("public_key", sshkey.Spec.PublicKey)
+	_ = d.Set("fingerprint", sshkey.Spec.Fingerprint)
 	return nil
 }
 
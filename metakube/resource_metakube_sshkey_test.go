@@ -61,6 +61,7 @@ func TestAccMetakubeSSHKey_Basic(t *testing.T) {
 					testAccCheckMetaKubeSSHKeyAttributes(&sshkey, testName),
 					resource.TestCheckResourceAttr(resourceName, "name", testName),
 					resource.TestCheckResourceAttr(resourceName, "public_key", testSSHPubKey),
+					resource.TestCheckResourceAttrSet(resourceName, "fingerprint"),
 				),
 			},
 			{
@@ -68,6 +69,18 @@ func TestAccMetakubeSSHKey_Basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("not found")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project_id"], rs.Primary.ID), nil
+				},
+			},
 			// Test importing non-existent resource provides expected error.
 			{
 				ResourceName:      resourceName,
@@ -177,3 +190,25 @@ func testAccCheckMetaKubeSSHKeyAttributes(rec *models.SSHKey, name string) resou
 		return nil
 	}
 }
+
+func TestValidateSSHPublicKey(t *testing.T) {
+	cases := []struct {
+		Input         string
+		ExpectedError bool
+	}{
+		{testSSHPubKey, false},
+		{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4f", false},
+		{"ssh-rsa AAAAC3NzaC1lZDI1NTE5AAAAIAABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4f", true},
+		{"ssh-rsa not-base64!", true},
+		{"ssh-dss AAAAB3NzaC1kc3M=", true},
+		{"garbage", true},
+		{"", true},
+	}
+
+	for _, tc := range cases {
+		_, errs := validateSSHPublicKey(tc.Input, "public_key")
+		if (len(errs) > 0) != tc.ExpectedError {
+			t.Fatalf("Unexpected validation result for '%s': %v", tc.Input, errs)
+		}
+	}
+}
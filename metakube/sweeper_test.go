@@ -9,7 +9,7 @@ import (
 
 func sharedConfigForRegion(_ string) (*metakubeProviderMeta, error) {
 	host := os.Getenv("METAKUBE_HOST")
-	client, err := newClient(host)
+	client, err := newClient(host, defaultAPITimeout, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create client %v", err)
 	}
@@ -20,8 +20,9 @@ func sharedConfigForRegion(_ string) (*metakubeProviderMeta, error) {
 	}
 	log := zap.NewNop().Sugar()
 	return &metakubeProviderMeta{
-		client,
-		auth,
-		log,
+		client:     client,
+		auth:       auth,
+		log:        log,
+		apiTimeout: defaultAPITimeout,
 	}, nil
 }